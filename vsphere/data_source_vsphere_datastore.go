@@ -0,0 +1,96 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/vim25/mo"
+	"golang.org/x/net/context"
+)
+
+func dataSourceVSphereDatastore() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceVSphereDatastoreRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"datacenter": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			// computed properties returned by the API
+			"type": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"url": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			// Capacity in MB
+			"capacity": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			// Free space in MB
+			"free_space": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"accessible": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"maintenance_mode": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceVSphereDatastoreRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	name := d.Get("name").(string)
+
+	dc, err := getDatacenter(client, d.Get("datacenter").(string))
+	if err != nil {
+		return fmt.Errorf("error finding datacenter: %s", err)
+	}
+	finder := find.NewFinder(client.Client, true)
+	finder = finder.SetDatacenter(dc)
+
+	ds, err := finder.Datastore(context.TODO(), name)
+	if err != nil {
+		return fmt.Errorf("error finding datastore %s: %s", name, err)
+	}
+
+	var mds mo.Datastore
+	if err := ds.Properties(context.TODO(), ds.Reference(), []string{"summary"}, &mds); err != nil {
+		return fmt.Errorf("error reading datastore %s: %s", name, err)
+	}
+	log.Printf("[DEBUG] datastore summary: %#v", mds.Summary)
+
+	d.SetId(ds.Reference().Value)
+	d.Set("type", mds.Summary.Type)
+	d.Set("url", mds.Summary.Url)
+	d.Set("capacity", mds.Summary.Capacity/1024/1024)
+	d.Set("free_space", mds.Summary.FreeSpace/1024/1024)
+	d.Set("accessible", mds.Summary.Accessible)
+	d.Set("maintenance_mode", mds.Summary.MaintenanceMode)
+
+	return nil
+}
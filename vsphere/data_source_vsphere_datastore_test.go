@@ -0,0 +1,48 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceVSphereDatastore_basic(t *testing.T) {
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+	datastore := os.Getenv("VSPHERE_DATASTORE")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccDataSourceVSphereDatastorePreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourceVSphereDatastoreConfig, datastore, datacenter),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("data.vsphere_datastore.ds", "id", regexp.MustCompile("^datastore-")),
+					resource.TestCheckResourceAttr("data.vsphere_datastore.ds", "accessible", "true"),
+					resource.TestMatchResourceAttr("data.vsphere_datastore.ds", "capacity", regexp.MustCompile("^[1-9][0-9]*$")),
+					resource.TestMatchResourceAttr("data.vsphere_datastore.ds", "free_space", regexp.MustCompile("^[0-9]+$")),
+					resource.TestMatchResourceAttr("data.vsphere_datastore.ds", "type", regexp.MustCompile("^(VMFS|NFS|NFS41|CIFS|VFAT|vsan|VVOL)$")),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceVSphereDatastorePreCheck(t *testing.T) {
+	if v := os.Getenv("VSPHERE_DATASTORE"); v == "" {
+		t.Fatal("env variable VSPHERE_DATASTORE must be set for acceptance tests")
+	}
+}
+
+const testAccDataSourceVSphereDatastoreConfig = `
+data "vsphere_datastore" "ds" {
+  name       = "%s"
+  datacenter = "%s"
+}
+`
@@ -0,0 +1,124 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+// networkTypes are the managed object types that can back a virtual network
+// interface.
+var networkTypes = []string{
+	"DistributedVirtualPortgroup",
+	"Network",
+	"OpaqueNetwork",
+}
+
+func dataSourceVSphereNetwork() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceVSphereNetworkRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"datacenter": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			// computed properties returned by the API
+			"type": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"distributed_virtual_switch_uuid": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceVSphereNetworkRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	name := d.Get("name").(string)
+
+	dc, err := getDatacenter(client, d.Get("datacenter").(string))
+	if err != nil {
+		return fmt.Errorf("error finding datacenter: %s", err)
+	}
+	finder := find.NewFinder(client.Client, true)
+	finder = finder.SetDatacenter(dc)
+
+	network, err := finder.Network(context.TODO(), name)
+	if err != nil {
+		return fmt.Errorf("error finding network %s: %s", name, err)
+	}
+	ref := network.Reference()
+	log.Printf("[DEBUG] network %s: %#v", name, ref)
+
+	d.SetId(ref.Value)
+	d.Set("type", ref.Type)
+
+	var dvsUUID string
+	if ref.Type == "DistributedVirtualPortgroup" {
+		dvsUUID, err = getPortgroupSwitchUUID(client, ref)
+		if err != nil {
+			return err
+		}
+	}
+	d.Set("distributed_virtual_switch_uuid", dvsUUID)
+
+	return nil
+}
+
+// getPortgroupSwitchUUID returns the UUID of the distributed virtual switch
+// that a port group belongs to.
+func getPortgroupSwitchUUID(c *govmomi.Client, ref types.ManagedObjectReference) (string, error) {
+	collector := property.DefaultCollector(c.Client)
+
+	var dvp mo.DistributedVirtualPortgroup
+	if err := collector.RetrieveOne(context.TODO(), ref, []string{"config.distributedVirtualSwitch"}, &dvp); err != nil {
+		return "", fmt.Errorf("error reading port group %s: %s", ref.Value, err)
+	}
+	if dvp.Config.DistributedVirtualSwitch == nil {
+		return "", fmt.Errorf("port group %s is not attached to a distributed virtual switch", ref.Value)
+	}
+
+	var dvs mo.DistributedVirtualSwitch
+	if err := collector.RetrieveOne(context.TODO(), *dvp.Config.DistributedVirtualSwitch, []string{"uuid"}, &dvs); err != nil {
+		return "", fmt.Errorf("error reading distributed virtual switch of port group %s: %s", ref.Value, err)
+	}
+	return dvs.Uuid, nil
+}
+
+// networkFromID returns the network with the given managed object ID. The ID
+// does not carry the type of the network, so each network type is tried in
+// turn.
+func networkFromID(c *govmomi.Client, id string) (object.NetworkReference, error) {
+	collector := property.DefaultCollector(c.Client)
+	for _, t := range networkTypes {
+		ref := types.ManagedObjectReference{Type: t, Value: id}
+		var mn mo.Network
+		if err := collector.RetrieveOne(context.TODO(), ref, []string{"name"}, &mn); err != nil {
+			log.Printf("[DEBUG] network %s is not a %s: %s", id, t, err)
+			continue
+		}
+		if n, ok := object.NewReference(c.Client, ref).(object.NetworkReference); ok {
+			return n, nil
+		}
+	}
+	return nil, fmt.Errorf("network with ID %s not found", id)
+}
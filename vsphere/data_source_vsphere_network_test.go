@@ -0,0 +1,70 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceVSphereNetwork_basic(t *testing.T) {
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+	label := os.Getenv("VSPHERE_NETWORK_LABEL")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccDataSourceVSphereNetworkPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourceVSphereNetworkConfig, label, datacenter),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("data.vsphere_network.net", "id", regexp.MustCompile(".+")),
+					resource.TestMatchResourceAttr("data.vsphere_network.net", "type", regexp.MustCompile("^(Network|DistributedVirtualPortgroup|OpaqueNetwork)$")),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceVSphereNetwork_distributedPortgroup(t *testing.T) {
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+	portgroup := os.Getenv("VSPHERE_DVPG_LABEL")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if portgroup == "" {
+				t.Skip("set VSPHERE_DVPG_LABEL to run vsphere_network distributed port group acceptance tests")
+			}
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourceVSphereNetworkConfig, portgroup, datacenter),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("data.vsphere_network.net", "id", regexp.MustCompile("^dvportgroup-")),
+					resource.TestCheckResourceAttr("data.vsphere_network.net", "type", "DistributedVirtualPortgroup"),
+					resource.TestMatchResourceAttr("data.vsphere_network.net", "distributed_virtual_switch_uuid", regexp.MustCompile(".+")),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceVSphereNetworkPreCheck(t *testing.T) {
+	if v := os.Getenv("VSPHERE_NETWORK_LABEL"); v == "" {
+		t.Fatal("env variable VSPHERE_NETWORK_LABEL must be set for acceptance tests")
+	}
+}
+
+const testAccDataSourceVSphereNetworkConfig = `
+data "vsphere_network" "net" {
+  name       = "%s"
+  datacenter = "%s"
+}
+`
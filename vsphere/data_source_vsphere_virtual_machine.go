@@ -0,0 +1,302 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+// diskControllerTypeNames maps the device types of disk controllers to the
+// values accepted by the controller_type argument of the disk block.
+var diskControllerTypeNames = map[string]string{
+	"lsilogic":     "scsi-lsi-parallel",
+	"buslogic":     "scsi-buslogic",
+	"pvscsi":       "scsi-paravirtual",
+	"lsilogic-sas": "scsi-lsi-sas",
+	"ide":          "ide",
+}
+
+func dataSourceVSphereVirtualMachine() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceVSphereVirtualMachineRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"uuid"},
+			},
+
+			"uuid": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"name"},
+			},
+
+			"datacenter": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			// computed properties returned by the API
+			"template": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"guest_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"firmware": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"vcpu": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"memory": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"disk": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"size": &schema.Schema{
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+
+						"type": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"controller_type": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"datastore": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"vmdk": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+
+			"network_interface": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"adapter_type": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"label": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"network_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"mac_address": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+
+			"current_snapshot_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"snapshot": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"path": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"description": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"create_time": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	var vm *object.VirtualMachine
+	var err error
+	if v, ok := d.GetOk("uuid"); ok {
+		vm, err = virtualMachineFromUUID(client, v.(string))
+		if err != nil {
+			return fmt.Errorf("error finding virtual machine %s: %s", v.(string), err)
+		}
+	} else {
+		name := d.Get("name").(string)
+		if name == "" {
+			return fmt.Errorf("One of name or uuid must be set.")
+		}
+		dc, err := getDatacenter(client, d.Get("datacenter").(string))
+		if err != nil {
+			return fmt.Errorf("error finding datacenter: %s", err)
+		}
+		finder := find.NewFinder(client.Client, true)
+		finder = finder.SetDatacenter(dc)
+		vm, err = finder.VirtualMachine(context.TODO(), name)
+		if err != nil {
+			return fmt.Errorf("error finding virtual machine %s: %s", name, err)
+		}
+	}
+
+	var mvm mo.VirtualMachine
+	collector := property.DefaultCollector(client.Client)
+	if err := collector.RetrieveOne(context.TODO(), vm.Reference(), []string{"config", "snapshot"}, &mvm); err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] virtual machine config: %#v", mvm.Config)
+
+	d.SetId(mvm.Config.InstanceUuid)
+	d.Set("uuid", mvm.Config.InstanceUuid)
+	d.Set("template", mvm.Config.Template)
+	d.Set("guest_id", mvm.Config.GuestId)
+	d.Set("firmware", mvm.Config.Firmware)
+	d.Set("vcpu", mvm.Config.Hardware.NumCPU)
+	d.Set("memory", mvm.Config.Hardware.MemoryMB)
+
+	devices := object.VirtualDeviceList(mvm.Config.Hardware.Device)
+
+	disks := make([]map[string]interface{}, 0)
+	for _, device := range devices.SelectByType((*types.VirtualDisk)(nil)) {
+		vd := device.(*types.VirtualDisk)
+		disk := map[string]interface{}{
+			"size":            vd.CapacityInKB / 1024 / 1024,
+			"controller_type": diskControllerTypeNames[devices.Type(devices.FindByKey(vd.ControllerKey))],
+		}
+		if backing, ok := vd.Backing.(*types.VirtualDiskFlatVer2BackingInfo); ok {
+			disk["type"] = virtualDiskType(backing)
+			var dp object.DatastorePath
+			if dp.FromString(backing.FileName) {
+				disk["datastore"] = dp.Datastore
+				disk["vmdk"] = dp.Path
+			}
+		}
+		disks = append(disks, disk)
+	}
+	if err := d.Set("disk", disks); err != nil {
+		return fmt.Errorf("Invalid disks to set: %#v", disks)
+	}
+
+	networkInterfaces := make([]map[string]interface{}, 0)
+	for _, device := range devices.SelectByType((*types.VirtualEthernetCard)(nil)) {
+		nic := device.(types.BaseVirtualEthernetCard)
+		label, err := getNetworkName(client, vm, nic)
+		if err != nil {
+			return err
+		}
+		networkID, err := getNetworkID(client, vm, nic)
+		if err != nil {
+			return err
+		}
+		networkInterfaces = append(networkInterfaces, map[string]interface{}{
+			"adapter_type": devices.Type(device),
+			"label":        label,
+			"network_id":   networkID,
+			"mac_address":  nic.GetVirtualEthernetCard().MacAddress,
+		})
+	}
+	if err := d.Set("network_interface", networkInterfaces); err != nil {
+		return fmt.Errorf("Invalid network interfaces to set: %#v", networkInterfaces)
+	}
+
+	snapshots := make([]map[string]interface{}, 0)
+	var currentSnapshot string
+	if mvm.Snapshot != nil {
+		snapshots = flattenSnapshotTree("", mvm.Snapshot.RootSnapshotList)
+		if mvm.Snapshot.CurrentSnapshot != nil {
+			currentSnapshot = mvm.Snapshot.CurrentSnapshot.Value
+		}
+	}
+	d.Set("current_snapshot_id", currentSnapshot)
+	if err := d.Set("snapshot", snapshots); err != nil {
+		return fmt.Errorf("Invalid snapshots to set: %#v", snapshots)
+	}
+
+	return nil
+}
+
+// flattenSnapshotTree flattens a snapshot tree into a list, parents before
+// their children. The path of each snapshot is made of the names of the
+// snapshots leading to it, separated by slashes.
+func flattenSnapshotTree(parent string, trees []types.VirtualMachineSnapshotTree) []map[string]interface{} {
+	var snapshots []map[string]interface{}
+	for _, tree := range trees {
+		p := tree.Name
+		if parent != "" {
+			p = parent + "/" + tree.Name
+		}
+		snapshots = append(snapshots, map[string]interface{}{
+			"id":          tree.Snapshot.Value,
+			"name":        tree.Name,
+			"path":        p,
+			"description": tree.Description,
+			"create_time": tree.CreateTime.Format(time.RFC3339),
+		})
+		snapshots = append(snapshots, flattenSnapshotTree(p, tree.ChildSnapshotList)...)
+	}
+	return snapshots
+}
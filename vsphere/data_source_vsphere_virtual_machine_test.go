@@ -0,0 +1,105 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestAccDataSourceVSphereVirtualMachine_basic(t *testing.T) {
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+	template := os.Getenv("VSPHERE_TEMPLATE")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccDataSourceVSphereVirtualMachinePreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourceVSphereVirtualMachineConfig, template, datacenter),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.vsphere_virtual_machine.template", "id", "data.vsphere_virtual_machine.template", "uuid"),
+					resource.TestCheckResourceAttr("data.vsphere_virtual_machine.template", "template", "true"),
+					resource.TestMatchResourceAttr("data.vsphere_virtual_machine.template", "guest_id", regexp.MustCompile(".+")),
+					resource.TestMatchResourceAttr("data.vsphere_virtual_machine.template", "firmware", regexp.MustCompile("^(bios|efi)$")),
+					resource.TestMatchResourceAttr("data.vsphere_virtual_machine.template", "vcpu", regexp.MustCompile("^[1-9][0-9]*$")),
+					resource.TestMatchResourceAttr("data.vsphere_virtual_machine.template", "disk.0.size", regexp.MustCompile("^[0-9]+$")),
+					resource.TestCheckResourceAttrPair("data.vsphere_virtual_machine.by_uuid", "id", "data.vsphere_virtual_machine.template", "id"),
+					resource.TestCheckResourceAttrPair("data.vsphere_virtual_machine.by_uuid", "memory", "data.vsphere_virtual_machine.template", "memory"),
+				),
+			},
+		},
+	})
+}
+
+func TestFlattenSnapshotTree(t *testing.T) {
+	now := time.Date(2017, 8, 1, 12, 0, 0, 0, time.UTC)
+	trees := []types.VirtualMachineSnapshotTree{
+		{
+			Snapshot:   types.ManagedObjectReference{Type: "VirtualMachineSnapshot", Value: "snapshot-1"},
+			Name:       "root",
+			CreateTime: now,
+			ChildSnapshotList: []types.VirtualMachineSnapshotTree{
+				{
+					Snapshot:    types.ManagedObjectReference{Type: "VirtualMachineSnapshot", Value: "snapshot-2"},
+					Name:        "child",
+					Description: "a child",
+					CreateTime:  now,
+				},
+			},
+		},
+		{
+			Snapshot:   types.ManagedObjectReference{Type: "VirtualMachineSnapshot", Value: "snapshot-3"},
+			Name:       "other",
+			CreateTime: now,
+		},
+	}
+
+	snapshots := flattenSnapshotTree("", trees)
+	expected := []struct {
+		id   string
+		path string
+	}{
+		{"snapshot-1", "root"},
+		{"snapshot-2", "root/child"},
+		{"snapshot-3", "other"},
+	}
+	if len(snapshots) != len(expected) {
+		t.Fatalf("expected %d snapshots, got %d", len(expected), len(snapshots))
+	}
+	for i, e := range expected {
+		if snapshots[i]["id"] != e.id || snapshots[i]["path"] != e.path {
+			t.Fatalf("snapshot %d: expected %s at %q, got %s at %q", i, e.id, e.path, snapshots[i]["id"], snapshots[i]["path"])
+		}
+	}
+	if snapshots[1]["description"] != "a child" {
+		t.Fatalf("expected description to be copied, got %q", snapshots[1]["description"])
+	}
+	if snapshots[0]["create_time"] != "2017-08-01T12:00:00Z" {
+		t.Fatalf("unexpected create_time %q", snapshots[0]["create_time"])
+	}
+}
+
+func testAccDataSourceVSphereVirtualMachinePreCheck(t *testing.T) {
+	if v := os.Getenv("VSPHERE_TEMPLATE"); v == "" {
+		t.Fatal("env variable VSPHERE_TEMPLATE must be set for acceptance tests")
+	}
+}
+
+const testAccDataSourceVSphereVirtualMachineConfig = `
+data "vsphere_virtual_machine" "template" {
+  name       = "%s"
+  datacenter = "%s"
+}
+
+data "vsphere_virtual_machine" "by_uuid" {
+  uuid = "${data.vsphere_virtual_machine.template.id}"
+}
+`
@@ -63,13 +63,40 @@ func Provider() terraform.ResourceProvider {
 			},
 		},
 
+		DataSourcesMap: map[string]*schema.Resource{
+			"vsphere_datastore":       dataSourceVSphereDatastore(),
+			"vsphere_network":         dataSourceVSphereNetwork(),
+			"vsphere_virtual_machine": dataSourceVSphereVirtualMachine(),
+		},
+
 		ResourcesMap: map[string]*schema.Resource{
-			"vsphere_datacenter":      resourceVSphereDatacenter(),
-			"vsphere_file":            resourceVSphereFile(),
-			"vsphere_folder":          resourceVSphereFolder(),
-			"vsphere_virtual_disk":    resourceVSphereVirtualDisk(),
-			"vsphere_virtual_machine": resourceVSphereVirtualMachine(),
-			"vsphere_license":         resourceVSphereLicense(),
+			"vsphere_compute_cluster":                       resourceVSphereComputeCluster(),
+			"vsphere_compute_cluster_host_group":            resourceVSphereComputeClusterHostGroup(),
+			"vsphere_compute_cluster_vm_affinity_rule":      resourceVSphereComputeClusterVMAffinityRule(),
+			"vsphere_compute_cluster_vm_anti_affinity_rule": resourceVSphereComputeClusterVMAntiAffinityRule(),
+			"vsphere_compute_cluster_vm_group":              resourceVSphereComputeClusterVMGroup(),
+			"vsphere_compute_cluster_vm_host_rule":          resourceVSphereComputeClusterVMHostRule(),
+			"vsphere_custom_attribute":                      resourceVSphereCustomAttribute(),
+			"vsphere_datacenter":                            resourceVSphereDatacenter(),
+			"vsphere_datastore_cluster":                     resourceVSphereDatastoreCluster(),
+			"vsphere_distributed_port_group":                resourceVSphereDistributedPortGroup(),
+			"vsphere_distributed_virtual_switch":            resourceVSphereDistributedVirtualSwitch(),
+			"vsphere_entity_permission":                     resourceVSphereEntityPermission(),
+			"vsphere_file":                                  resourceVSphereFile(),
+			"vsphere_folder":                                resourceVSphereFolder(),
+			"vsphere_host":                                  resourceVSphereHost(),
+			"vsphere_host_port_group":                       resourceVSphereHostPortGroup(),
+			"vsphere_host_virtual_switch":                   resourceVSphereHostVirtualSwitch(),
+			"vsphere_nas_datastore":                         resourceVSphereNasDatastore(),
+			"vsphere_role":                                  resourceVSphereRole(),
+			"vsphere_vapp_container":                        resourceVSphereVAppContainer(),
+			"vsphere_virtual_disk":                          resourceVSphereVirtualDisk(),
+			"vsphere_virtual_machine":                       resourceVSphereVirtualMachine(),
+			"vsphere_virtual_machine_snapshot":              resourceVSphereVirtualMachineSnapshot(),
+			"vsphere_vmfs_datastore":                        resourceVSphereVmfsDatastore(),
+			"vsphere_vnic":                                  resourceVSphereVNIC(),
+			"vsphere_license":                               resourceVSphereLicense(),
+			"vsphere_resource_pool":                         resourceVSphereResourcePool(),
 		},
 
 		ConfigureFunc: providerConfigure,
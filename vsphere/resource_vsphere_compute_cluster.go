@@ -0,0 +1,410 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+var drsBehaviors = []string{
+	string(types.DrsBehaviorManual),
+	string(types.DrsBehaviorPartiallyAutomated),
+	string(types.DrsBehaviorFullyAutomated),
+}
+
+var haHostMonitoringStates = []string{
+	string(types.ClusterDasConfigInfoServiceStateEnabled),
+	string(types.ClusterDasConfigInfoServiceStateDisabled),
+}
+
+var haRestartPriorities = []string{
+	string(types.ClusterDasVmSettingsRestartPriorityDisabled),
+	string(types.ClusterDasVmSettingsRestartPriorityLowest),
+	string(types.ClusterDasVmSettingsRestartPriorityLow),
+	string(types.ClusterDasVmSettingsRestartPriorityMedium),
+	string(types.ClusterDasVmSettingsRestartPriorityHigh),
+	string(types.ClusterDasVmSettingsRestartPriorityHighest),
+}
+
+func resourceVSphereComputeCluster() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereComputeClusterCreate,
+		Read:   resourceVSphereComputeClusterRead,
+		Update: resourceVSphereComputeClusterUpdate,
+		Delete: resourceVSphereComputeClusterDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereComputeClusterImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"datacenter": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"folder": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"drs_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"drs_automation_level": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      string(types.DrsBehaviorManual),
+				ValidateFunc: validateStringInSlice(drsBehaviors),
+			},
+
+			"drs_migration_threshold": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  3,
+				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
+					value := v.(int)
+					if value < 1 || value > 5 {
+						errors = append(errors, fmt.Errorf(
+							"'%s' must be between 1 and 5", k))
+					}
+					return
+				},
+			},
+
+			"ha_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"ha_admission_control_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+
+			"ha_admission_control_host_failures": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  1,
+			},
+
+			"ha_host_monitoring": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      string(types.ClusterDasConfigInfoServiceStateEnabled),
+				ValidateFunc: validateStringInSlice(haHostMonitoringStates),
+			},
+
+			"ha_vm_restart_priority": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      string(types.ClusterDasVmSettingsRestartPriorityMedium),
+				ValidateFunc: validateStringInSlice(haRestartPriorities),
+			},
+
+			"resource_pool_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceVSphereComputeClusterCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	dc, err := getDatacenter(client, d.Get("datacenter").(string))
+	if err != nil {
+		return fmt.Errorf("error finding datacenter: %s", err)
+	}
+
+	var f *object.Folder
+	if v, ok := d.GetOk("folder"); ok {
+		finder := find.NewFinder(client.Client, true)
+		f, err = finder.Folder(context.TODO(), path.Join(dc.InventoryPath, "host", v.(string)))
+		if err != nil {
+			return fmt.Errorf("failed to find folder that will contain the cluster: %s", err)
+		}
+	} else {
+		dcFolders, err := dc.Folders(context.TODO())
+		if err != nil {
+			return err
+		}
+		f = dcFolders.HostFolder
+	}
+
+	name := d.Get("name").(string)
+	cluster, err := f.CreateCluster(context.TODO(), name, *computeClusterConfigSpec(d))
+	if err != nil {
+		return fmt.Errorf("error creating cluster %s: %s", name, err)
+	}
+	log.Printf("[INFO] Created cluster: %s", cluster.Reference().Value)
+
+	d.SetId(cluster.Reference().Value)
+
+	return resourceVSphereComputeClusterRead(d, meta)
+}
+
+func resourceVSphereComputeClusterRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	var mcr mo.ClusterComputeResource
+	collector := property.DefaultCollector(client.Client)
+	if err := collector.RetrieveOne(context.TODO(), computeClusterReference(d.Id()), []string{"name", "resourcePool", "configurationEx"}, &mcr); err != nil {
+		if isManagedObjectNotFoundError(err) {
+			log.Printf("[DEBUG] cluster %s not found: %s", d.Id(), err)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("name", mcr.Name)
+	if mcr.ResourcePool != nil {
+		d.Set("resource_pool_id", mcr.ResourcePool.Value)
+	}
+
+	config, ok := mcr.ConfigurationEx.(*types.ClusterConfigInfoEx)
+	if !ok {
+		return fmt.Errorf("unexpected configuration type %T for cluster %s", mcr.ConfigurationEx, d.Id())
+	}
+
+	drs := config.DrsConfig
+	if drs.Enabled != nil {
+		d.Set("drs_enabled", *drs.Enabled)
+	}
+	d.Set("drs_automation_level", string(drs.DefaultVmBehavior))
+	d.Set("drs_migration_threshold", drs.VmotionRate)
+
+	das := config.DasConfig
+	if das.Enabled != nil {
+		d.Set("ha_enabled", *das.Enabled)
+	}
+	if das.AdmissionControlEnabled != nil {
+		d.Set("ha_admission_control_enabled", *das.AdmissionControlEnabled)
+	}
+	if policy, ok := das.AdmissionControlPolicy.(*types.ClusterFailoverLevelAdmissionControlPolicy); ok {
+		d.Set("ha_admission_control_host_failures", policy.FailoverLevel)
+	}
+	d.Set("ha_host_monitoring", das.HostMonitoring)
+	if das.DefaultVmSettings != nil {
+		d.Set("ha_vm_restart_priority", das.DefaultVmSettings.RestartPriority)
+	}
+
+	return nil
+}
+
+func resourceVSphereComputeClusterUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	cluster := object.NewClusterComputeResource(client.Client, computeClusterReference(d.Id()))
+
+	if d.HasChange("name") {
+		task, err := cluster.Rename(context.TODO(), d.Get("name").(string))
+		if err != nil {
+			return fmt.Errorf("error renaming cluster %s: %s", d.Id(), err)
+		}
+		if err := task.Wait(context.TODO()); err != nil {
+			return fmt.Errorf("error renaming cluster %s: %s", d.Id(), err)
+		}
+	}
+
+	task, err := cluster.Reconfigure(context.TODO(), computeClusterConfigSpec(d), true)
+	if err != nil {
+		return fmt.Errorf("error reconfiguring cluster %s: %s", d.Id(), err)
+	}
+	if err := task.Wait(context.TODO()); err != nil {
+		return fmt.Errorf("error reconfiguring cluster %s: %s", d.Id(), err)
+	}
+
+	return resourceVSphereComputeClusterRead(d, meta)
+}
+
+func resourceVSphereComputeClusterDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	cluster := object.NewClusterComputeResource(client.Client, computeClusterReference(d.Id()))
+
+	task, err := cluster.Destroy(context.TODO())
+	if err != nil {
+		return fmt.Errorf("error destroying cluster %s: %s", d.Id(), err)
+	}
+	if err := task.Wait(context.TODO()); err != nil {
+		return fmt.Errorf("error destroying cluster %s: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceVSphereComputeClusterImport imports a cluster by its inventory
+// path, such as "/dc1/host/prod/cluster1".
+func resourceVSphereComputeClusterImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*govmomi.Client)
+
+	ref, err := object.NewSearchIndex(client.Client).FindByInventoryPath(context.TODO(), d.Id())
+	if err != nil {
+		return nil, err
+	}
+	cluster, ok := ref.(*object.ClusterComputeResource)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a cluster", d.Id())
+	}
+
+	dcPath, err := datacenterInventoryPath(client, ref.Reference())
+	if err != nil {
+		return nil, err
+	}
+	folder, _, err := splitDatacenterInventoryPath(dcPath, "host", d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("datacenter", strings.TrimPrefix(dcPath, "/"))
+	d.Set("folder", folder)
+	d.SetId(cluster.Reference().Value)
+	return []*schema.ResourceData{d}, nil
+}
+
+func computeClusterReference(id string) types.ManagedObjectReference {
+	return types.ManagedObjectReference{
+		Type:  "ClusterComputeResource",
+		Value: id,
+	}
+}
+
+func computeClusterConfigSpec(d *schema.ResourceData) *types.ClusterConfigSpecEx {
+	return &types.ClusterConfigSpecEx{
+		DrsConfig: &types.ClusterDrsConfigInfo{
+			Enabled:           types.NewBool(d.Get("drs_enabled").(bool)),
+			DefaultVmBehavior: types.DrsBehavior(d.Get("drs_automation_level").(string)),
+			VmotionRate:       int32(d.Get("drs_migration_threshold").(int)),
+		},
+		DasConfig: &types.ClusterDasConfigInfo{
+			Enabled:                 types.NewBool(d.Get("ha_enabled").(bool)),
+			AdmissionControlEnabled: types.NewBool(d.Get("ha_admission_control_enabled").(bool)),
+			AdmissionControlPolicy: &types.ClusterFailoverLevelAdmissionControlPolicy{
+				FailoverLevel: int32(d.Get("ha_admission_control_host_failures").(int)),
+			},
+			HostMonitoring: d.Get("ha_host_monitoring").(string),
+			DefaultVmSettings: &types.ClusterDasVmSettings{
+				RestartPriority: d.Get("ha_vm_restart_priority").(string),
+			},
+		},
+	}
+}
+
+// computeClusterMemberID returns the ID of a group or rule of a cluster, in
+// the form "<compute_cluster_id>:<name or key>".
+func computeClusterMemberID(clusterID, name string) string {
+	return clusterID + ":" + name
+}
+
+// splitComputeClusterMemberID splits an ID built by computeClusterMemberID.
+func splitComputeClusterMemberID(id string) (string, string, error) {
+	parts := strings.SplitN(id, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid ID %q, expected <compute_cluster_id>:<name>", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+// computeClusterConfig returns the configuration of a cluster, including its
+// DRS groups and rules.
+func computeClusterConfig(client *govmomi.Client, clusterID string) (*types.ClusterConfigInfoEx, error) {
+	var mcr mo.ClusterComputeResource
+	collector := property.DefaultCollector(client.Client)
+	if err := collector.RetrieveOne(context.TODO(), computeClusterReference(clusterID), []string{"configurationEx"}, &mcr); err != nil {
+		return nil, err
+	}
+	config, ok := mcr.ConfigurationEx.(*types.ClusterConfigInfoEx)
+	if !ok {
+		return nil, fmt.Errorf("unexpected configuration type %T for cluster %s", mcr.ConfigurationEx, clusterID)
+	}
+	return config, nil
+}
+
+// computeClusterReconfigure applies an incremental configuration change, such
+// as a group or rule change, to a cluster.
+func computeClusterReconfigure(client *govmomi.Client, clusterID string, spec *types.ClusterConfigSpecEx) error {
+	cluster := object.NewClusterComputeResource(client.Client, computeClusterReference(clusterID))
+	task, err := cluster.Reconfigure(context.TODO(), spec, true)
+	if err != nil {
+		return err
+	}
+	return task.Wait(context.TODO())
+}
+
+// computeClusterGroup returns the group of a cluster with the given name, or
+// nil if there is none.
+func computeClusterGroup(config *types.ClusterConfigInfoEx, name string) types.BaseClusterGroupInfo {
+	for _, g := range config.Group {
+		if g.GetClusterGroupInfo().Name == name {
+			return g
+		}
+	}
+	return nil
+}
+
+// computeClusterRuleByKey returns the rule of a cluster with the given key, or
+// nil if there is none.
+func computeClusterRuleByKey(config *types.ClusterConfigInfoEx, key int32) types.BaseClusterRuleInfo {
+	for _, r := range config.Rule {
+		if r.GetClusterRuleInfo().Key == key {
+			return r
+		}
+	}
+	return nil
+}
+
+// computeClusterRuleByName returns the rule of a cluster with the given name,
+// or nil if there is none.
+func computeClusterRuleByName(config *types.ClusterConfigInfoEx, name string) types.BaseClusterRuleInfo {
+	for _, r := range config.Rule {
+		if r.GetClusterRuleInfo().Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// managedObjectReferences builds references of the given type from a set of
+// managed object IDs.
+func managedObjectReferences(kind string, ids []interface{}) []types.ManagedObjectReference {
+	var refs []types.ManagedObjectReference
+	for _, id := range ids {
+		refs = append(refs, types.ManagedObjectReference{
+			Type:  kind,
+			Value: id.(string),
+		})
+	}
+	return refs
+}
+
+// managedObjectIDs returns the IDs of a list of managed object references.
+func managedObjectIDs(refs []types.ManagedObjectReference) []string {
+	var ids []string
+	for _, ref := range refs {
+		ids = append(ids, ref.Value)
+	}
+	return ids
+}
@@ -0,0 +1,129 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func resourceVSphereComputeClusterHostGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereComputeClusterHostGroupCreate,
+		Read:   resourceVSphereComputeClusterHostGroupRead,
+		Update: resourceVSphereComputeClusterHostGroupUpdate,
+		Delete: resourceVSphereComputeClusterHostGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"compute_cluster_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"host_system_ids": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceVSphereComputeClusterHostGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	clusterID := d.Get("compute_cluster_id").(string)
+	name := d.Get("name").(string)
+
+	if err := computeClusterHostGroupApply(client, d, types.ArrayUpdateOperationAdd); err != nil {
+		return fmt.Errorf("error creating host group %s: %s", name, err)
+	}
+	log.Printf("[INFO] Created host group: %s", name)
+
+	d.SetId(computeClusterMemberID(clusterID, name))
+
+	return resourceVSphereComputeClusterHostGroupRead(d, meta)
+}
+
+func resourceVSphereComputeClusterHostGroupRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	clusterID, name, err := splitComputeClusterMemberID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	config, err := computeClusterConfig(client, clusterID)
+	if err != nil {
+		if isManagedObjectNotFoundError(err) {
+			log.Printf("[DEBUG] cluster %s not found: %s", clusterID, err)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	group, ok := computeClusterGroup(config, name).(*types.ClusterHostGroup)
+	if !ok {
+		log.Printf("[DEBUG] host group %s not found", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("compute_cluster_id", clusterID)
+	d.Set("name", group.Name)
+	if err := d.Set("host_system_ids", managedObjectIDs(group.Host)); err != nil {
+		return fmt.Errorf("Invalid host_system_ids to set: %#v", group.Host)
+	}
+
+	return nil
+}
+
+func resourceVSphereComputeClusterHostGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	if err := computeClusterHostGroupApply(client, d, types.ArrayUpdateOperationEdit); err != nil {
+		return fmt.Errorf("error updating host group %s: %s", d.Id(), err)
+	}
+
+	return resourceVSphereComputeClusterHostGroupRead(d, meta)
+}
+
+func resourceVSphereComputeClusterHostGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	if err := computeClusterGroupRemove(client, d); err != nil {
+		return fmt.Errorf("error destroying host group %s: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func computeClusterHostGroupApply(client *govmomi.Client, d *schema.ResourceData, op types.ArrayUpdateOperation) error {
+	spec := &types.ClusterConfigSpecEx{
+		GroupSpec: []types.ClusterGroupSpec{
+			{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: op},
+				Info: &types.ClusterHostGroup{
+					ClusterGroupInfo: types.ClusterGroupInfo{
+						Name: d.Get("name").(string),
+					},
+					Host: managedObjectReferences("HostSystem", d.Get("host_system_ids").(*schema.Set).List()),
+				},
+			},
+		},
+	}
+	return computeClusterReconfigure(client, d.Get("compute_cluster_id").(string), spec)
+}
@@ -0,0 +1,58 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccVSphereComputeClusterHostGroup_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccVSphereComputeClusterMemberPreCheck(t)
+			if v := os.Getenv("VSPHERE_HOST_SYSTEM_ID"); v == "" {
+				t.Skip("set VSPHERE_HOST_SYSTEM_ID to a host in VSPHERE_CLUSTER to run vsphere_compute_cluster_host_group acceptance tests")
+			}
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereComputeClusterGroupDestroy("vsphere_compute_cluster_host_group"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckVSphereComputeClusterHostGroupConfig(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereComputeClusterGroupExists("vsphere_compute_cluster_host_group.group"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster_host_group.group", "host_system_ids.#", "1"),
+				),
+			},
+			{
+				Config: testAccCheckVSphereComputeClusterHostGroupConfig(false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereComputeClusterGroupExists("vsphere_compute_cluster_host_group.group"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster_host_group.group", "host_system_ids.#", "0"),
+				),
+			},
+			{
+				ResourceName:      "vsphere_compute_cluster_host_group.group",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckVSphereComputeClusterHostGroupConfig(withHost bool) string {
+	var hosts string
+	if withHost {
+		hosts = fmt.Sprintf("%q", os.Getenv("VSPHERE_HOST_SYSTEM_ID"))
+	}
+	return fmt.Sprintf(`
+resource "vsphere_compute_cluster_host_group" "group" {
+  compute_cluster_id = "%s"
+  name               = "terraform-test-host-group"
+  host_system_ids    = [%s]
+}
+`, os.Getenv("VSPHERE_COMPUTE_CLUSTER_ID"), hosts)
+}
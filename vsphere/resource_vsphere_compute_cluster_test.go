@@ -0,0 +1,146 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/mo"
+	"golang.org/x/net/context"
+)
+
+func TestAccVSphereComputeCluster_basic(t *testing.T) {
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereComputeClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereComputeClusterConfig, datacenter),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereComputeClusterExists("vsphere_compute_cluster.cluster"),
+					resource.TestMatchResourceAttr("vsphere_compute_cluster.cluster", "resource_pool_id", regexp.MustCompile("^resgroup-")),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster.cluster", "drs_enabled", "true"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster.cluster", "drs_automation_level", "fullyAutomated"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster.cluster", "drs_migration_threshold", "3"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster.cluster", "ha_enabled", "false"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereComputeClusterConfigUpdated, datacenter),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereComputeClusterExists("vsphere_compute_cluster.cluster"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster.cluster", "name", "terraform-test-cluster-renamed"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster.cluster", "drs_automation_level", "partiallyAutomated"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster.cluster", "drs_migration_threshold", "2"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster.cluster", "ha_enabled", "true"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster.cluster", "ha_admission_control_enabled", "false"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster.cluster", "ha_host_monitoring", "disabled"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster.cluster", "ha_vm_restart_priority", "high"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccVSphereComputeCluster_importBasic(t *testing.T) {
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereComputeClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereComputeClusterConfig, datacenter),
+			},
+			{
+				ResourceName:      "vsphere_compute_cluster.cluster",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("/%s/host/terraform-test-cluster", datacenter),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckVSphereComputeClusterDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*govmomi.Client)
+	collector := property.DefaultCollector(client.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "vsphere_compute_cluster" {
+			continue
+		}
+
+		var mcr mo.ClusterComputeResource
+		err := collector.RetrieveOne(context.TODO(), computeClusterReference(rs.Primary.ID), []string{"name"}, &mcr)
+		if err == nil {
+			return fmt.Errorf("cluster %s still exists", rs.Primary.ID)
+		}
+		if !isManagedObjectNotFoundError(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckVSphereComputeClusterExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*govmomi.Client)
+		collector := property.DefaultCollector(client.Client)
+
+		var mcr mo.ClusterComputeResource
+		if err := collector.RetrieveOne(context.TODO(), computeClusterReference(rs.Primary.ID), []string{"name"}, &mcr); err != nil {
+			return fmt.Errorf("error finding cluster %s: %s", rs.Primary.ID, err)
+		}
+		if mcr.Name != rs.Primary.Attributes["name"] {
+			return fmt.Errorf("expected cluster name %s, got %s", rs.Primary.Attributes["name"], mcr.Name)
+		}
+
+		return nil
+	}
+}
+
+const testAccCheckVSphereComputeClusterConfig = `
+resource "vsphere_compute_cluster" "cluster" {
+  name       = "terraform-test-cluster"
+  datacenter = "%s"
+
+  drs_enabled          = true
+  drs_automation_level = "fullyAutomated"
+}
+`
+
+const testAccCheckVSphereComputeClusterConfigUpdated = `
+resource "vsphere_compute_cluster" "cluster" {
+  name       = "terraform-test-cluster-renamed"
+  datacenter = "%s"
+
+  drs_enabled             = true
+  drs_automation_level    = "partiallyAutomated"
+  drs_migration_threshold = 2
+
+  ha_enabled                   = true
+  ha_admission_control_enabled = false
+  ha_host_monitoring           = "disabled"
+  ha_vm_restart_priority       = "high"
+}
+`
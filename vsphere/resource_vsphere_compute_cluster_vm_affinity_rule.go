@@ -0,0 +1,259 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func resourceVSphereComputeClusterVMAffinityRule() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereComputeClusterVMAffinityRuleCreate,
+		Read:   resourceVSphereComputeClusterVMAffinityRuleRead,
+		Update: resourceVSphereComputeClusterVMAffinityRuleUpdate,
+		Delete: resourceVSphereComputeClusterRuleDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereComputeClusterRuleImport,
+		},
+
+		Schema: computeClusterVMRuleSchema(),
+	}
+}
+
+// computeClusterVMRuleSchema returns the arguments shared by the VM-VM
+// affinity and anti-affinity rule resources.
+func computeClusterVMRuleSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"compute_cluster_id": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+
+		"name": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+		},
+
+		"virtual_machine_ids": &schema.Schema{
+			Type:     schema.TypeSet,
+			Required: true,
+			MinItems: 2,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+
+		"enabled": &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+
+		"mandatory": &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	}
+}
+
+func resourceVSphereComputeClusterVMAffinityRuleCreate(d *schema.ResourceData, meta interface{}) error {
+	return computeClusterRuleCreate(d, meta, &types.ClusterAffinityRuleSpec{
+		ClusterRuleInfo: computeClusterRuleInfo(d),
+		Vm:              managedObjectReferences("VirtualMachine", d.Get("virtual_machine_ids").(*schema.Set).List()),
+	}, resourceVSphereComputeClusterVMAffinityRuleRead)
+}
+
+func resourceVSphereComputeClusterVMAffinityRuleRead(d *schema.ResourceData, meta interface{}) error {
+	rule, err := computeClusterRuleRead(d, meta)
+	if err != nil || rule == nil {
+		return err
+	}
+	r, ok := rule.(*types.ClusterAffinityRuleSpec)
+	if !ok {
+		return fmt.Errorf("rule %s is a %T, not a VM affinity rule", d.Id(), rule)
+	}
+	if err := d.Set("virtual_machine_ids", managedObjectIDs(r.Vm)); err != nil {
+		return fmt.Errorf("Invalid virtual_machine_ids to set: %#v", r.Vm)
+	}
+	return nil
+}
+
+func resourceVSphereComputeClusterVMAffinityRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := computeClusterRuleUpdate(d, meta, &types.ClusterAffinityRuleSpec{
+		ClusterRuleInfo: computeClusterRuleInfo(d),
+		Vm:              managedObjectReferences("VirtualMachine", d.Get("virtual_machine_ids").(*schema.Set).List()),
+	}); err != nil {
+		return err
+	}
+	return resourceVSphereComputeClusterVMAffinityRuleRead(d, meta)
+}
+
+// computeClusterRuleInfo returns the settings shared by all rule types. The
+// key is set from the ID of an existing rule.
+func computeClusterRuleInfo(d *schema.ResourceData) types.ClusterRuleInfo {
+	info := types.ClusterRuleInfo{
+		Name:      d.Get("name").(string),
+		Enabled:   types.NewBool(d.Get("enabled").(bool)),
+		Mandatory: types.NewBool(d.Get("mandatory").(bool)),
+	}
+	if _, key, err := splitComputeClusterMemberID(d.Id()); err == nil {
+		if k, err := strconv.Atoi(key); err == nil {
+			info.Key = int32(k)
+		}
+	}
+	return info
+}
+
+// computeClusterRuleCreate adds a rule to a cluster and sets the ID from the
+// key vSphere assigns to it.
+func computeClusterRuleCreate(d *schema.ResourceData, meta interface{}, info types.BaseClusterRuleInfo, read schema.ReadFunc) error {
+	client := meta.(*govmomi.Client)
+	clusterID := d.Get("compute_cluster_id").(string)
+	name := d.Get("name").(string)
+
+	// Rules are found by name after they are added, so refuse to add a rule
+	// that would share its name with an existing one instead of adopting it.
+	config, err := computeClusterConfig(client, clusterID)
+	if err != nil {
+		return err
+	}
+	if computeClusterRuleByName(config, name) != nil {
+		return fmt.Errorf("rule %s already exists in cluster %s", name, clusterID)
+	}
+
+	spec := &types.ClusterConfigSpecEx{
+		RulesSpec: []types.ClusterRuleSpec{
+			{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: types.ArrayUpdateOperationAdd},
+				Info:            info,
+			},
+		},
+	}
+	if err := computeClusterReconfigure(client, clusterID, spec); err != nil {
+		return fmt.Errorf("error creating rule %s: %s", name, err)
+	}
+
+	config, err = computeClusterConfig(client, clusterID)
+	if err != nil {
+		return err
+	}
+	rule := computeClusterRuleByName(config, name)
+	if rule == nil {
+		return fmt.Errorf("rule %s not found after creation", name)
+	}
+	key := rule.GetClusterRuleInfo().Key
+	log.Printf("[INFO] Created rule: %s (%d)", name, key)
+
+	d.SetId(computeClusterMemberID(clusterID, strconv.Itoa(int(key))))
+
+	return read(d, meta)
+}
+
+// computeClusterRuleRead reads the settings shared by all rule types and
+// returns the rule, or nil if it is gone.
+func computeClusterRuleRead(d *schema.ResourceData, meta interface{}) (types.BaseClusterRuleInfo, error) {
+	client := meta.(*govmomi.Client)
+
+	clusterID, key, err := splitComputeClusterMemberID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	k, err := strconv.Atoi(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule key %q: %s", key, err)
+	}
+
+	config, err := computeClusterConfig(client, clusterID)
+	if err != nil {
+		if isManagedObjectNotFoundError(err) {
+			log.Printf("[DEBUG] cluster %s not found: %s", clusterID, err)
+			d.SetId("")
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	rule := computeClusterRuleByKey(config, int32(k))
+	if rule == nil {
+		log.Printf("[DEBUG] rule %s not found", d.Id())
+		d.SetId("")
+		return nil, nil
+	}
+
+	info := rule.GetClusterRuleInfo()
+	d.Set("compute_cluster_id", clusterID)
+	d.Set("name", info.Name)
+	if info.Enabled != nil {
+		d.Set("enabled", *info.Enabled)
+	}
+	if info.Mandatory != nil {
+		d.Set("mandatory", *info.Mandatory)
+	}
+
+	return rule, nil
+}
+
+func computeClusterRuleUpdate(d *schema.ResourceData, meta interface{}, info types.BaseClusterRuleInfo) error {
+	client := meta.(*govmomi.Client)
+
+	spec := &types.ClusterConfigSpecEx{
+		RulesSpec: []types.ClusterRuleSpec{
+			{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: types.ArrayUpdateOperationEdit},
+				Info:            info,
+			},
+		},
+	}
+	if err := computeClusterReconfigure(client, d.Get("compute_cluster_id").(string), spec); err != nil {
+		return fmt.Errorf("error updating rule %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+func resourceVSphereComputeClusterRuleDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	spec := &types.ClusterConfigSpecEx{
+		RulesSpec: []types.ClusterRuleSpec{
+			{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{
+					Operation: types.ArrayUpdateOperationRemove,
+					RemoveKey: computeClusterRuleInfo(d).Key,
+				},
+			},
+		},
+	}
+	if err := computeClusterReconfigure(client, d.Get("compute_cluster_id").(string), spec); err != nil {
+		return fmt.Errorf("error destroying rule %s: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceVSphereComputeClusterRuleImport imports a rule by the ID of its
+// cluster and its name, such as "domain-c7:db-anti-affinity".
+func resourceVSphereComputeClusterRuleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*govmomi.Client)
+
+	clusterID, name, err := splitComputeClusterMemberID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := computeClusterConfig(client, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	rule := computeClusterRuleByName(config, name)
+	if rule == nil {
+		return nil, fmt.Errorf("rule %s not found in cluster %s", name, clusterID)
+	}
+
+	d.SetId(computeClusterMemberID(clusterID, strconv.Itoa(int(rule.GetClusterRuleInfo().Key))))
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,129 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/vmware/govmomi"
+)
+
+func TestAccVSphereComputeClusterVMAffinityRule_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccVSphereComputeClusterMemberPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereComputeClusterRuleDestroy("vsphere_compute_cluster_vm_affinity_rule"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckVSphereComputeClusterVMAffinityRuleConfig("terraform-test-affinity", "false"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereComputeClusterRuleExists("vsphere_compute_cluster_vm_affinity_rule.rule"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster_vm_affinity_rule.rule", "virtual_machine_ids.#", "2"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster_vm_affinity_rule.rule", "mandatory", "false"),
+				),
+			},
+			{
+				Config: testAccCheckVSphereComputeClusterVMAffinityRuleConfig("terraform-test-affinity-renamed", "true"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereComputeClusterRuleExists("vsphere_compute_cluster_vm_affinity_rule.rule"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster_vm_affinity_rule.rule", "name", "terraform-test-affinity-renamed"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster_vm_affinity_rule.rule", "mandatory", "true"),
+				),
+			},
+			{
+				Config:      testAccCheckVSphereComputeClusterVMAffinityRuleConfigDuplicate("terraform-test-affinity-renamed"),
+				ExpectError: regexp.MustCompile("rule terraform-test-affinity-renamed already exists"),
+			},
+		},
+	})
+}
+
+func testAccCheckVSphereComputeClusterRuleDestroy(resourceType string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*govmomi.Client)
+
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+
+			found, err := testAccComputeClusterRuleExists(client, rs.Primary.ID)
+			if err != nil {
+				return err
+			}
+			if found {
+				return fmt.Errorf("rule %s still exists", rs.Primary.ID)
+			}
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckVSphereComputeClusterRuleExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*govmomi.Client)
+		found, err := testAccComputeClusterRuleExists(client, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("rule %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccComputeClusterRuleExists(client *govmomi.Client, id string) (bool, error) {
+	clusterID, key, err := splitComputeClusterMemberID(id)
+	if err != nil {
+		return false, err
+	}
+	k, err := strconv.Atoi(key)
+	if err != nil {
+		return false, err
+	}
+	config, err := computeClusterConfig(client, clusterID)
+	if err != nil {
+		return false, err
+	}
+	return computeClusterRuleByKey(config, int32(k)) != nil, nil
+}
+
+func testAccCheckVSphereComputeClusterVMAffinityRuleConfig(name, mandatory string) string {
+	return testAccCheckVSphereComputeClusterVMsConfig(2) + fmt.Sprintf(`
+resource "vsphere_compute_cluster_vm_affinity_rule" "rule" {
+  compute_cluster_id  = "%s"
+  name                = "%s"
+  virtual_machine_ids = ["${vsphere_virtual_machine.vm.*.moid}"]
+  mandatory           = %s
+}
+`, os.Getenv("VSPHERE_COMPUTE_CLUSTER_ID"), name, mandatory)
+}
+
+func testAccCheckVSphereComputeClusterVMAffinityRuleConfigDuplicate(name string) string {
+	return testAccCheckVSphereComputeClusterVMAffinityRuleConfig(name, "true") + fmt.Sprintf(`
+resource "vsphere_compute_cluster_vm_affinity_rule" "duplicate" {
+  compute_cluster_id  = "%s"
+  name                = "%s"
+  virtual_machine_ids = ["${vsphere_virtual_machine.vm.*.moid}"]
+}
+`, os.Getenv("VSPHERE_COMPUTE_CLUSTER_ID"), name)
+}
@@ -0,0 +1,54 @@
+package vsphere
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func resourceVSphereComputeClusterVMAntiAffinityRule() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereComputeClusterVMAntiAffinityRuleCreate,
+		Read:   resourceVSphereComputeClusterVMAntiAffinityRuleRead,
+		Update: resourceVSphereComputeClusterVMAntiAffinityRuleUpdate,
+		Delete: resourceVSphereComputeClusterRuleDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereComputeClusterRuleImport,
+		},
+
+		Schema: computeClusterVMRuleSchema(),
+	}
+}
+
+func resourceVSphereComputeClusterVMAntiAffinityRuleCreate(d *schema.ResourceData, meta interface{}) error {
+	return computeClusterRuleCreate(d, meta, &types.ClusterAntiAffinityRuleSpec{
+		ClusterRuleInfo: computeClusterRuleInfo(d),
+		Vm:              managedObjectReferences("VirtualMachine", d.Get("virtual_machine_ids").(*schema.Set).List()),
+	}, resourceVSphereComputeClusterVMAntiAffinityRuleRead)
+}
+
+func resourceVSphereComputeClusterVMAntiAffinityRuleRead(d *schema.ResourceData, meta interface{}) error {
+	rule, err := computeClusterRuleRead(d, meta)
+	if err != nil || rule == nil {
+		return err
+	}
+	r, ok := rule.(*types.ClusterAntiAffinityRuleSpec)
+	if !ok {
+		return fmt.Errorf("rule %s is a %T, not a VM anti-affinity rule", d.Id(), rule)
+	}
+	if err := d.Set("virtual_machine_ids", managedObjectIDs(r.Vm)); err != nil {
+		return fmt.Errorf("Invalid virtual_machine_ids to set: %#v", r.Vm)
+	}
+	return nil
+}
+
+func resourceVSphereComputeClusterVMAntiAffinityRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := computeClusterRuleUpdate(d, meta, &types.ClusterAntiAffinityRuleSpec{
+		ClusterRuleInfo: computeClusterRuleInfo(d),
+		Vm:              managedObjectReferences("VirtualMachine", d.Get("virtual_machine_ids").(*schema.Set).List()),
+	}); err != nil {
+		return err
+	}
+	return resourceVSphereComputeClusterVMAntiAffinityRuleRead(d, meta)
+}
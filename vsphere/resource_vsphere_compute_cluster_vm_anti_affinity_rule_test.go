@@ -0,0 +1,55 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccVSphereComputeClusterVMAntiAffinityRule_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccVSphereComputeClusterMemberPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereComputeClusterRuleDestroy("vsphere_compute_cluster_vm_anti_affinity_rule"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckVSphereComputeClusterVMAntiAffinityRuleConfig(3, "true"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereComputeClusterRuleExists("vsphere_compute_cluster_vm_anti_affinity_rule.rule"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster_vm_anti_affinity_rule.rule", "virtual_machine_ids.#", "3"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster_vm_anti_affinity_rule.rule", "enabled", "true"),
+				),
+			},
+			{
+				Config: testAccCheckVSphereComputeClusterVMAntiAffinityRuleConfig(2, "false"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereComputeClusterRuleExists("vsphere_compute_cluster_vm_anti_affinity_rule.rule"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster_vm_anti_affinity_rule.rule", "virtual_machine_ids.#", "2"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster_vm_anti_affinity_rule.rule", "enabled", "false"),
+				),
+			},
+			{
+				ResourceName:      "vsphere_compute_cluster_vm_anti_affinity_rule.rule",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s:terraform-test-anti-affinity", os.Getenv("VSPHERE_COMPUTE_CLUSTER_ID")),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckVSphereComputeClusterVMAntiAffinityRuleConfig(count int, enabled string) string {
+	return testAccCheckVSphereComputeClusterVMsConfig(count) + fmt.Sprintf(`
+resource "vsphere_compute_cluster_vm_anti_affinity_rule" "rule" {
+  compute_cluster_id  = "%s"
+  name                = "terraform-test-anti-affinity"
+  virtual_machine_ids = ["${vsphere_virtual_machine.vm.*.moid}"]
+  enabled             = %s
+}
+`, os.Getenv("VSPHERE_COMPUTE_CLUSTER_ID"), enabled)
+}
@@ -0,0 +1,145 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func resourceVSphereComputeClusterVMGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereComputeClusterVMGroupCreate,
+		Read:   resourceVSphereComputeClusterVMGroupRead,
+		Update: resourceVSphereComputeClusterVMGroupUpdate,
+		Delete: resourceVSphereComputeClusterVMGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"compute_cluster_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"virtual_machine_ids": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceVSphereComputeClusterVMGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	clusterID := d.Get("compute_cluster_id").(string)
+	name := d.Get("name").(string)
+
+	if err := computeClusterVMGroupApply(client, d, types.ArrayUpdateOperationAdd); err != nil {
+		return fmt.Errorf("error creating VM group %s: %s", name, err)
+	}
+	log.Printf("[INFO] Created VM group: %s", name)
+
+	d.SetId(computeClusterMemberID(clusterID, name))
+
+	return resourceVSphereComputeClusterVMGroupRead(d, meta)
+}
+
+func resourceVSphereComputeClusterVMGroupRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	clusterID, name, err := splitComputeClusterMemberID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	config, err := computeClusterConfig(client, clusterID)
+	if err != nil {
+		if isManagedObjectNotFoundError(err) {
+			log.Printf("[DEBUG] cluster %s not found: %s", clusterID, err)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	group, ok := computeClusterGroup(config, name).(*types.ClusterVmGroup)
+	if !ok {
+		log.Printf("[DEBUG] VM group %s not found", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("compute_cluster_id", clusterID)
+	d.Set("name", group.Name)
+	if err := d.Set("virtual_machine_ids", managedObjectIDs(group.Vm)); err != nil {
+		return fmt.Errorf("Invalid virtual_machine_ids to set: %#v", group.Vm)
+	}
+
+	return nil
+}
+
+func resourceVSphereComputeClusterVMGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	if err := computeClusterVMGroupApply(client, d, types.ArrayUpdateOperationEdit); err != nil {
+		return fmt.Errorf("error updating VM group %s: %s", d.Id(), err)
+	}
+
+	return resourceVSphereComputeClusterVMGroupRead(d, meta)
+}
+
+func resourceVSphereComputeClusterVMGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	if err := computeClusterGroupRemove(client, d); err != nil {
+		return fmt.Errorf("error destroying VM group %s: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func computeClusterVMGroupApply(client *govmomi.Client, d *schema.ResourceData, op types.ArrayUpdateOperation) error {
+	spec := &types.ClusterConfigSpecEx{
+		GroupSpec: []types.ClusterGroupSpec{
+			{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: op},
+				Info: &types.ClusterVmGroup{
+					ClusterGroupInfo: types.ClusterGroupInfo{
+						Name: d.Get("name").(string),
+					},
+					Vm: managedObjectReferences("VirtualMachine", d.Get("virtual_machine_ids").(*schema.Set).List()),
+				},
+			},
+		},
+	}
+	return computeClusterReconfigure(client, d.Get("compute_cluster_id").(string), spec)
+}
+
+// computeClusterGroupRemove removes the VM or host group of a
+// vsphere_compute_cluster_vm_group or vsphere_compute_cluster_host_group.
+func computeClusterGroupRemove(client *govmomi.Client, d *schema.ResourceData) error {
+	spec := &types.ClusterConfigSpecEx{
+		GroupSpec: []types.ClusterGroupSpec{
+			{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{
+					Operation: types.ArrayUpdateOperationRemove,
+					RemoveKey: d.Get("name").(string),
+				},
+			},
+		},
+	}
+	return computeClusterReconfigure(client, d.Get("compute_cluster_id").(string), spec)
+}
@@ -0,0 +1,157 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/vmware/govmomi"
+)
+
+func TestAccVSphereComputeClusterVMGroup_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccVSphereComputeClusterMemberPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereComputeClusterGroupDestroy("vsphere_compute_cluster_vm_group"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckVSphereComputeClusterVMGroupConfig(2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereComputeClusterGroupExists("vsphere_compute_cluster_vm_group.group"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster_vm_group.group", "virtual_machine_ids.#", "2"),
+				),
+			},
+			{
+				Config: testAccCheckVSphereComputeClusterVMGroupConfig(3),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereComputeClusterGroupExists("vsphere_compute_cluster_vm_group.group"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster_vm_group.group", "virtual_machine_ids.#", "3"),
+				),
+			},
+			{
+				ResourceName:      "vsphere_compute_cluster_vm_group.group",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestSplitComputeClusterMemberID(t *testing.T) {
+	clusterID, name, err := splitComputeClusterMemberID("domain-c7:db:replicas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clusterID != "domain-c7" || name != "db:replicas" {
+		t.Fatalf("unexpected parts %q, %q", clusterID, name)
+	}
+
+	if _, _, err := splitComputeClusterMemberID("domain-c7"); err == nil {
+		t.Fatal("expected an error for an ID without name")
+	}
+}
+
+func testAccVSphereComputeClusterMemberPreCheck(t *testing.T) {
+	if v := os.Getenv("VSPHERE_COMPUTE_CLUSTER_ID"); v == "" {
+		t.Skip("set VSPHERE_COMPUTE_CLUSTER_ID to the ID of VSPHERE_CLUSTER to run DRS group and rule acceptance tests")
+	}
+}
+
+func testAccCheckVSphereComputeClusterGroupDestroy(resourceType string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*govmomi.Client)
+
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+
+			clusterID, name, err := splitComputeClusterMemberID(rs.Primary.ID)
+			if err != nil {
+				return err
+			}
+			config, err := computeClusterConfig(client, clusterID)
+			if err != nil {
+				return err
+			}
+			if computeClusterGroup(config, name) != nil {
+				return fmt.Errorf("group %s still exists", rs.Primary.ID)
+			}
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckVSphereComputeClusterGroupExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*govmomi.Client)
+		clusterID, name, err := splitComputeClusterMemberID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		config, err := computeClusterConfig(client, clusterID)
+		if err != nil {
+			return err
+		}
+		if computeClusterGroup(config, name) == nil {
+			return fmt.Errorf("group %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+// testAccCheckVSphereComputeClusterVMsConfig returns count virtual machines in
+// VSPHERE_CLUSTER to use as members of DRS groups and rules.
+func testAccCheckVSphereComputeClusterVMsConfig(count int) string {
+	return fmt.Sprintf(`
+resource "vsphere_virtual_machine" "vm" {
+  count      = %d
+  name       = "terraform-test-drs-${count.index}"
+  datacenter = "%s"
+  cluster    = "%s"
+  vcpu       = 1
+  memory     = 512
+
+  network_interface {
+    label = "%s"
+  }
+
+  disk {
+    size      = 1
+    datastore = "%s"
+  }
+}
+`,
+		count,
+		os.Getenv("VSPHERE_DATACENTER"),
+		os.Getenv("VSPHERE_CLUSTER"),
+		os.Getenv("VSPHERE_NETWORK_LABEL"),
+		os.Getenv("VSPHERE_DATASTORE"),
+	)
+}
+
+func testAccCheckVSphereComputeClusterVMGroupConfig(count int) string {
+	return testAccCheckVSphereComputeClusterVMsConfig(count) + fmt.Sprintf(`
+resource "vsphere_compute_cluster_vm_group" "group" {
+  compute_cluster_id  = "%s"
+  name                = "terraform-test-vm-group"
+  virtual_machine_ids = ["${vsphere_virtual_machine.vm.*.moid}"]
+}
+`, os.Getenv("VSPHERE_COMPUTE_CLUSTER_ID"))
+}
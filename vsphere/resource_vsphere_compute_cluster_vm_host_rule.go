@@ -0,0 +1,109 @@
+package vsphere
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func resourceVSphereComputeClusterVMHostRule() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereComputeClusterVMHostRuleCreate,
+		Read:   resourceVSphereComputeClusterVMHostRuleRead,
+		Update: resourceVSphereComputeClusterVMHostRuleUpdate,
+		Delete: resourceVSphereComputeClusterRuleDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereComputeClusterRuleImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"compute_cluster_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"vm_group_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"affinity_host_group_name": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"anti_affinity_host_group_name"},
+			},
+
+			"anti_affinity_host_group_name": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"affinity_host_group_name"},
+			},
+
+			"enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+
+			"mandatory": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+func resourceVSphereComputeClusterVMHostRuleCreate(d *schema.ResourceData, meta interface{}) error {
+	info, err := computeClusterVMHostRuleInfo(d)
+	if err != nil {
+		return err
+	}
+	return computeClusterRuleCreate(d, meta, info, resourceVSphereComputeClusterVMHostRuleRead)
+}
+
+func resourceVSphereComputeClusterVMHostRuleRead(d *schema.ResourceData, meta interface{}) error {
+	rule, err := computeClusterRuleRead(d, meta)
+	if err != nil || rule == nil {
+		return err
+	}
+	r, ok := rule.(*types.ClusterVmHostRuleInfo)
+	if !ok {
+		return fmt.Errorf("rule %s is a %T, not a VM-host rule", d.Id(), rule)
+	}
+	d.Set("vm_group_name", r.VmGroupName)
+	d.Set("affinity_host_group_name", r.AffineHostGroupName)
+	d.Set("anti_affinity_host_group_name", r.AntiAffineHostGroupName)
+	return nil
+}
+
+func resourceVSphereComputeClusterVMHostRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+	info, err := computeClusterVMHostRuleInfo(d)
+	if err != nil {
+		return err
+	}
+	if err := computeClusterRuleUpdate(d, meta, info); err != nil {
+		return err
+	}
+	return resourceVSphereComputeClusterVMHostRuleRead(d, meta)
+}
+
+func computeClusterVMHostRuleInfo(d *schema.ResourceData) (*types.ClusterVmHostRuleInfo, error) {
+	info := &types.ClusterVmHostRuleInfo{
+		ClusterRuleInfo:         computeClusterRuleInfo(d),
+		VmGroupName:             d.Get("vm_group_name").(string),
+		AffineHostGroupName:     d.Get("affinity_host_group_name").(string),
+		AntiAffineHostGroupName: d.Get("anti_affinity_host_group_name").(string),
+	}
+	if info.AffineHostGroupName == "" && info.AntiAffineHostGroupName == "" {
+		return nil, fmt.Errorf("one of affinity_host_group_name or anti_affinity_host_group_name must be set")
+	}
+	return info, nil
+}
@@ -0,0 +1,59 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccVSphereComputeClusterVMHostRule_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccVSphereComputeClusterMemberPreCheck(t)
+			if v := os.Getenv("VSPHERE_HOST_SYSTEM_ID"); v == "" {
+				t.Skip("set VSPHERE_HOST_SYSTEM_ID to a host in VSPHERE_CLUSTER to run vsphere_compute_cluster_vm_host_rule acceptance tests")
+			}
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereComputeClusterRuleDestroy("vsphere_compute_cluster_vm_host_rule"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckVSphereComputeClusterVMHostRuleConfig("affinity_host_group_name"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereComputeClusterRuleExists("vsphere_compute_cluster_vm_host_rule.rule"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster_vm_host_rule.rule", "affinity_host_group_name", "terraform-test-host-group"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster_vm_host_rule.rule", "anti_affinity_host_group_name", ""),
+				),
+			},
+			{
+				Config: testAccCheckVSphereComputeClusterVMHostRuleConfig("anti_affinity_host_group_name"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereComputeClusterRuleExists("vsphere_compute_cluster_vm_host_rule.rule"),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster_vm_host_rule.rule", "affinity_host_group_name", ""),
+					resource.TestCheckResourceAttr("vsphere_compute_cluster_vm_host_rule.rule", "anti_affinity_host_group_name", "terraform-test-host-group"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckVSphereComputeClusterVMHostRuleConfig(hostGroupArg string) string {
+	clusterID := os.Getenv("VSPHERE_COMPUTE_CLUSTER_ID")
+	return testAccCheckVSphereComputeClusterVMGroupConfig(2) + fmt.Sprintf(`
+resource "vsphere_compute_cluster_host_group" "group" {
+  compute_cluster_id = "%s"
+  name               = "terraform-test-host-group"
+  host_system_ids    = ["%s"]
+}
+
+resource "vsphere_compute_cluster_vm_host_rule" "rule" {
+  compute_cluster_id = "%s"
+  name               = "terraform-test-vm-host-rule"
+  vm_group_name      = "${vsphere_compute_cluster_vm_group.group.name}"
+  %s = "${vsphere_compute_cluster_host_group.group.name}"
+}
+`, clusterID, os.Getenv("VSPHERE_HOST_SYSTEM_ID"), clusterID, hostGroupArg)
+}
@@ -0,0 +1,268 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+func resourceVSphereCustomAttribute() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereCustomAttributeCreate,
+		Read:   resourceVSphereCustomAttributeRead,
+		Update: resourceVSphereCustomAttributeUpdate,
+		Delete: resourceVSphereCustomAttributeDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereCustomAttributeImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			// The managed object type the attribute applies to, such as
+			// VirtualMachine. Empty for attributes that apply to all types.
+			"managed_object_type": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceVSphereCustomAttributeCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	m, err := object.GetCustomFieldsManager(client.Client)
+	if err != nil {
+		return err
+	}
+
+	name := d.Get("name").(string)
+	def, err := m.Add(context.TODO(), name, d.Get("managed_object_type").(string), nil, nil)
+	if err != nil {
+		return fmt.Errorf("error creating custom attribute %s: %s", name, err)
+	}
+	log.Printf("[INFO] Created custom attribute %s: %d", name, def.Key)
+
+	d.SetId(strconv.Itoa(int(def.Key)))
+
+	return resourceVSphereCustomAttributeRead(d, meta)
+}
+
+func resourceVSphereCustomAttributeRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	key, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("invalid custom attribute ID %q: %s", d.Id(), err)
+	}
+
+	m, err := object.GetCustomFieldsManager(client.Client)
+	if err != nil {
+		return err
+	}
+	defs, err := m.Field(context.TODO())
+	if err != nil {
+		return fmt.Errorf("error reading custom attributes: %s", err)
+	}
+
+	for _, def := range defs {
+		if def.Key == int32(key) {
+			d.Set("name", def.Name)
+			d.Set("managed_object_type", def.ManagedObjectType)
+			return nil
+		}
+	}
+
+	log.Printf("[DEBUG] custom attribute %s not found", d.Id())
+	d.SetId("")
+	return nil
+}
+
+func resourceVSphereCustomAttributeUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	key, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("invalid custom attribute ID %q: %s", d.Id(), err)
+	}
+
+	if d.HasChange("name") {
+		m, err := object.GetCustomFieldsManager(client.Client)
+		if err != nil {
+			return err
+		}
+		if err := m.Rename(context.TODO(), int32(key), d.Get("name").(string)); err != nil {
+			return fmt.Errorf("error renaming custom attribute %s: %s", d.Id(), err)
+		}
+	}
+
+	return resourceVSphereCustomAttributeRead(d, meta)
+}
+
+func resourceVSphereCustomAttributeDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	key, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("invalid custom attribute ID %q: %s", d.Id(), err)
+	}
+
+	m, err := object.GetCustomFieldsManager(client.Client)
+	if err != nil {
+		return err
+	}
+	if err := m.Remove(context.TODO(), int32(key)); err != nil {
+		return fmt.Errorf("error removing custom attribute %s: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceVSphereCustomAttributeImport imports a custom attribute by its
+// name.
+func resourceVSphereCustomAttributeImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*govmomi.Client)
+
+	m, err := object.GetCustomFieldsManager(client.Client)
+	if err != nil {
+		return nil, err
+	}
+	key, err := m.FindKey(context.TODO(), d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("error finding custom attribute %s: %s", d.Id(), err)
+	}
+
+	d.SetId(strconv.Itoa(int(key)))
+	return []*schema.ResourceData{d}, nil
+}
+
+// customAttributesSchema is the schema of the custom_attributes argument of
+// the resources that support custom attributes. The map is keyed by
+// attribute name.
+func customAttributesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeMap,
+		Optional: true,
+	}
+}
+
+// applyCustomAttributes sets the custom attributes of an entity that changed
+// in custom_attributes. Attributes removed from the map are cleared.
+func applyCustomAttributes(client *govmomi.Client, ref types.ManagedObjectReference, d *schema.ResourceData) error {
+	if !d.HasChange("custom_attributes") {
+		return nil
+	}
+
+	m, err := object.GetCustomFieldsManager(client.Client)
+	if err != nil {
+		return err
+	}
+	defs, err := m.Field(context.TODO())
+	if err != nil {
+		return fmt.Errorf("error reading custom attributes: %s", err)
+	}
+	keys := make(map[string]int32)
+	for _, def := range defs {
+		keys[def.Name] = def.Key
+	}
+
+	set := func(name, value string) error {
+		key, ok := keys[name]
+		if !ok {
+			return fmt.Errorf("custom attribute %s not found", name)
+		}
+		if err := m.Set(context.TODO(), ref, key, value); err != nil {
+			return fmt.Errorf("error setting custom attribute %s on %s: %s", name, ref.Value, err)
+		}
+		return nil
+	}
+
+	o, n := d.GetChange("custom_attributes")
+	oldAttrs := o.(map[string]interface{})
+	newAttrs := n.(map[string]interface{})
+	for name := range oldAttrs {
+		if _, ok := newAttrs[name]; !ok {
+			if err := set(name, ""); err != nil {
+				return err
+			}
+		}
+	}
+	for name, value := range newAttrs {
+		if oldAttrs[name] != value {
+			if err := set(name, value.(string)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readCustomAttributes sets custom_attributes to the values of the custom
+// attributes set on an entity, limited to the attributes in custom_attributes.
+func readCustomAttributes(client *govmomi.Client, ref types.ManagedObjectReference, d *schema.ResourceData) error {
+	var me mo.ManagedEntity
+	collector := property.DefaultCollector(client.Client)
+	if err := collector.RetrieveOne(context.TODO(), ref, []string{"customValue"}, &me); err != nil {
+		return fmt.Errorf("error reading custom attributes of %s: %s", ref.Value, err)
+	}
+	if len(me.CustomValue) == 0 {
+		d.Set("custom_attributes", map[string]interface{}{})
+		return nil
+	}
+
+	m, err := object.GetCustomFieldsManager(client.Client)
+	if err != nil {
+		return err
+	}
+	defs, err := m.Field(context.TODO())
+	if err != nil {
+		return fmt.Errorf("error reading custom attributes: %s", err)
+	}
+	names := make(map[int32]string)
+	for _, def := range defs {
+		names[def.Key] = def.Name
+	}
+
+	values := make(map[string]string)
+	for _, v := range me.CustomValue {
+		value, ok := v.(*types.CustomFieldStringValue)
+		if !ok || value.Value == "" {
+			continue
+		}
+		if name, ok := names[value.Key]; ok {
+			values[name] = value.Value
+		}
+	}
+	attrs := trackedCustomAttributes(values, d.Get("custom_attributes").(map[string]interface{}))
+	if err := d.Set("custom_attributes", attrs); err != nil {
+		return fmt.Errorf("Invalid custom_attributes to set: %#v", attrs)
+	}
+	return nil
+}
+
+// trackedCustomAttributes returns the custom attribute values of an entity
+// that are managed by custom_attributes. Attributes set by other tools are
+// ignored, so that Terraform does not try to clear them.
+func trackedCustomAttributes(values map[string]string, tracked map[string]interface{}) map[string]interface{} {
+	attrs := make(map[string]interface{})
+	for name := range tracked {
+		if value, ok := values[name]; ok {
+			attrs[name] = value
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,169 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"golang.org/x/net/context"
+)
+
+func TestAccVSphereCustomAttribute_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereCustomAttributeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereCustomAttributeConfig, "terraform-test-attribute"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereCustomAttributeExists("vsphere_custom_attribute.attribute"),
+					resource.TestCheckResourceAttr("vsphere_custom_attribute.attribute", "managed_object_type", "Folder"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereCustomAttributeConfig, "terraform-test-attribute-renamed"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereCustomAttributeExists("vsphere_custom_attribute.attribute"),
+					resource.TestCheckResourceAttr("vsphere_custom_attribute.attribute", "name", "terraform-test-attribute-renamed"),
+				),
+			},
+			{
+				ResourceName:      "vsphere_custom_attribute.attribute",
+				ImportState:       true,
+				ImportStateId:     "terraform-test-attribute-renamed",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestTrackedCustomAttributes(t *testing.T) {
+	values := map[string]string{
+		"owner":       "alice",
+		"last-backup": "2017-08-01",
+	}
+	tracked := map[string]interface{}{
+		"owner": "bob",
+		"team":  "web",
+	}
+
+	attrs := trackedCustomAttributes(values, tracked)
+	if len(attrs) != 1 || attrs["owner"] != "alice" {
+		t.Fatalf("expected only the owner attribute, got %#v", attrs)
+	}
+}
+
+func TestAccVSphereCustomAttribute_folder(t *testing.T) {
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereCustomAttributeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereCustomAttributeConfigFolder, datacenter, "alice"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vsphere_folder.folder", "custom_attributes.%", "1"),
+					resource.TestCheckResourceAttr("vsphere_folder.folder", "custom_attributes.terraform-test-owner", "alice"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereCustomAttributeConfigFolder, datacenter, "bob"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vsphere_folder.folder", "custom_attributes.terraform-test-owner", "bob"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckVSphereCustomAttributeDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "vsphere_custom_attribute" {
+			continue
+		}
+
+		found, err := testAccCustomAttributeExists(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if found {
+			return fmt.Errorf("custom attribute %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckVSphereCustomAttributeExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		found, err := testAccCustomAttributeExists(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("custom attribute %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccCustomAttributeExists(id string) (bool, error) {
+	client := testAccProvider.Meta().(*govmomi.Client)
+	key, err := strconv.Atoi(id)
+	if err != nil {
+		return false, err
+	}
+
+	m, err := object.GetCustomFieldsManager(client.Client)
+	if err != nil {
+		return false, err
+	}
+	defs, err := m.Field(context.TODO())
+	if err != nil {
+		return false, err
+	}
+	for _, def := range defs {
+		if def.Key == int32(key) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+const testAccCheckVSphereCustomAttributeConfig = `
+resource "vsphere_custom_attribute" "attribute" {
+  name                = "%s"
+  managed_object_type = "Folder"
+}
+`
+
+const testAccCheckVSphereCustomAttributeConfigFolder = `
+resource "vsphere_custom_attribute" "owner" {
+  name = "terraform-test-owner"
+}
+
+resource "vsphere_folder" "folder" {
+  datacenter = "%s"
+  path       = "terraform-test-attribute-folder"
+
+  custom_attributes = "${map(vsphere_custom_attribute.owner.name, "%s")}"
+}
+`
@@ -3,6 +3,8 @@ package vsphere
 import (
 	"fmt"
 	"log"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/resource"
@@ -19,7 +21,11 @@ func resourceVSphereDatacenter() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceVSphereDatacenterCreate,
 		Read:   resourceVSphereDatacenterRead,
+		Update: resourceVSphereDatacenterUpdate,
 		Delete: resourceVSphereDatacenterDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereDatacenterImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
@@ -32,6 +38,11 @@ func resourceVSphereDatacenter() *schema.Resource {
 				Optional: true,
 				ForceNew: true,
 			},
+			"custom_attributes": customAttributesSchema(),
+			"moid": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 	}
 }
@@ -78,6 +89,10 @@ func resourceVSphereDatacenterCreate(d *schema.ResourceData, meta interface{}) e
 
 	d.SetId(name)
 
+	if err := applyCustomAttributes(client, dc.Reference(), d); err != nil {
+		return err
+	}
+
 	return resourceVSphereDatacenterRead(d, meta)
 
 }
@@ -115,13 +130,48 @@ func datacenterExists(d *schema.ResourceData, meta interface{}) (*object.Datacen
 }
 
 func resourceVSphereDatacenterRead(d *schema.ResourceData, meta interface{}) error {
-	_, err := datacenterExists(d, meta)
+	dc, err := datacenterExists(d, meta)
 	if err != nil {
 		log.Printf("couldn't find the specified datacenter: %s", err)
 		d.SetId("")
+		return nil
 	}
 
-	return nil
+	d.Set("moid", dc.Reference().Value)
+	return readCustomAttributes(meta.(*govmomi.Client), dc.Reference(), d)
+}
+
+func resourceVSphereDatacenterUpdate(d *schema.ResourceData, meta interface{}) error {
+	dc, err := datacenterExists(d, meta)
+	if err != nil {
+		return fmt.Errorf("error finding datacenter %s: %s", d.Id(), err)
+	}
+
+	if err := applyCustomAttributes(meta.(*govmomi.Client), dc.Reference(), d); err != nil {
+		return err
+	}
+
+	return resourceVSphereDatacenterRead(d, meta)
+}
+
+// resourceVSphereDatacenterImport imports a datacenter by its inventory path,
+// such as "/research/my_datacenter".
+func resourceVSphereDatacenterImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	p := strings.TrimRight(d.Id(), "/")
+	name := path.Base(p)
+	folder := strings.TrimLeft(path.Dir(p), "/")
+	if folder == "." {
+		folder = ""
+	}
+
+	d.Set("name", name)
+	d.Set("folder", folder)
+	if _, err := datacenterExists(d, meta); err != nil {
+		return nil, fmt.Errorf("error finding datacenter %s: %s", d.Id(), err)
+	}
+
+	d.SetId(name)
+	return []*schema.ResourceData{d}, nil
 }
 
 func resourceVSphereDatacenterDelete(d *schema.ResourceData, meta interface{}) error {
@@ -60,6 +60,25 @@ func TestAccVSphereDatacenter_createOnSubfolder(t *testing.T) {
 	})
 }
 
+func TestAccVSphereDatacenter_importBasic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereDatacenterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckVSphereDatacenterConfig,
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     "/testDC",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func testAccCheckVSphereDatacenterDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*govmomi.Client)
 	finder := find.NewFinder(client.Client, true)
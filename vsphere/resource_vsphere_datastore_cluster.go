@@ -0,0 +1,397 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+var sdrsBehaviors = []string{
+	string(types.StorageDrsPodConfigInfoBehaviorManual),
+	string(types.StorageDrsPodConfigInfoBehaviorAutomated),
+}
+
+var sdrsSpaceThresholdModes = []string{
+	string(types.StorageDrsSpaceLoadBalanceConfigSpaceThresholdModeUtilization),
+	string(types.StorageDrsSpaceLoadBalanceConfigSpaceThresholdModeFreeSpace),
+}
+
+func resourceVSphereDatastoreCluster() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereDatastoreClusterCreate,
+		Read:   resourceVSphereDatastoreClusterRead,
+		Update: resourceVSphereDatastoreClusterUpdate,
+		Delete: resourceVSphereDatastoreClusterDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereDatastoreClusterImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"datacenter": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"folder": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"datastore_ids": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"sdrs_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"sdrs_automation_level": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      string(types.StorageDrsPodConfigInfoBehaviorManual),
+				ValidateFunc: validateStringInSlice(sdrsBehaviors),
+			},
+
+			// Minutes between load balancing runs.
+			"sdrs_load_balance_interval": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  480,
+			},
+
+			"sdrs_default_intra_vm_affinity": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+
+			"sdrs_space_threshold_mode": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      string(types.StorageDrsSpaceLoadBalanceConfigSpaceThresholdModeUtilization),
+				ValidateFunc: validateStringInSlice(sdrsSpaceThresholdModes),
+			},
+
+			// Percentage of used space above which datastores are balanced.
+			"sdrs_space_utilization_threshold": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  80,
+			},
+
+			// Free space in GB below which datastores are balanced.
+			"sdrs_free_space_threshold": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  50,
+			},
+
+			"sdrs_space_utilization_difference": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  5,
+			},
+
+			"sdrs_io_load_balance_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+
+			// Latency in milliseconds above which datastores are balanced.
+			"sdrs_io_latency_threshold": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  15,
+			},
+
+			"sdrs_io_load_imbalance_threshold": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  5,
+			},
+		},
+	}
+}
+
+func resourceVSphereDatastoreClusterCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	dc, err := getDatacenter(client, d.Get("datacenter").(string))
+	if err != nil {
+		return fmt.Errorf("error finding datacenter: %s", err)
+	}
+
+	var f *object.Folder
+	if v, ok := d.GetOk("folder"); ok {
+		finder := find.NewFinder(client.Client, true)
+		f, err = finder.Folder(context.TODO(), path.Join(dc.InventoryPath, "datastore", v.(string)))
+		if err != nil {
+			return fmt.Errorf("failed to find folder that will contain the datastore cluster: %s", err)
+		}
+	} else {
+		dcFolders, err := dc.Folders(context.TODO())
+		if err != nil {
+			return err
+		}
+		f = dcFolders.DatastoreFolder
+	}
+
+	name := d.Get("name").(string)
+	pod, err := f.CreateStoragePod(context.TODO(), name)
+	if err != nil {
+		return fmt.Errorf("error creating datastore cluster %s: %s", name, err)
+	}
+	log.Printf("[INFO] Created datastore cluster: %s", pod.Reference().Value)
+
+	d.SetId(pod.Reference().Value)
+
+	if err := datastoreClusterConfigure(client, pod, d); err != nil {
+		return err
+	}
+
+	if v, ok := d.GetOk("datastore_ids"); ok {
+		if err := datastoreClusterMoveInto(pod.Folder, v.(*schema.Set).List()); err != nil {
+			return err
+		}
+	}
+
+	return resourceVSphereDatastoreClusterRead(d, meta)
+}
+
+func resourceVSphereDatastoreClusterRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	var msp mo.StoragePod
+	collector := property.DefaultCollector(client.Client)
+	if err := collector.RetrieveOne(context.TODO(), datastoreClusterReference(d.Id()), []string{"name", "childEntity", "podStorageDrsEntry"}, &msp); err != nil {
+		if isManagedObjectNotFoundError(err) {
+			log.Printf("[DEBUG] datastore cluster %s not found: %s", d.Id(), err)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("name", msp.Name)
+
+	var datastoreIDs []string
+	for _, child := range msp.ChildEntity {
+		if child.Type == "Datastore" {
+			datastoreIDs = append(datastoreIDs, child.Value)
+		}
+	}
+	if err := d.Set("datastore_ids", datastoreIDs); err != nil {
+		return fmt.Errorf("Invalid datastore_ids to set: %#v", datastoreIDs)
+	}
+
+	if msp.PodStorageDrsEntry == nil {
+		return nil
+	}
+	config := msp.PodStorageDrsEntry.StorageDrsConfig.PodConfig
+	d.Set("sdrs_enabled", config.Enabled)
+	d.Set("sdrs_automation_level", config.DefaultVmBehavior)
+	d.Set("sdrs_load_balance_interval", config.LoadBalanceInterval)
+	if config.DefaultIntraVmAffinity != nil {
+		d.Set("sdrs_default_intra_vm_affinity", *config.DefaultIntraVmAffinity)
+	}
+	if space := config.SpaceLoadBalanceConfig; space != nil {
+		d.Set("sdrs_space_threshold_mode", space.SpaceThresholdMode)
+		d.Set("sdrs_space_utilization_threshold", space.SpaceUtilizationThreshold)
+		d.Set("sdrs_free_space_threshold", space.FreeSpaceThresholdGB)
+		d.Set("sdrs_space_utilization_difference", space.MinSpaceUtilizationDifference)
+	}
+	d.Set("sdrs_io_load_balance_enabled", config.IoLoadBalanceEnabled)
+	if io := config.IoLoadBalanceConfig; io != nil {
+		d.Set("sdrs_io_latency_threshold", io.IoLatencyThreshold)
+		d.Set("sdrs_io_load_imbalance_threshold", io.IoLoadImbalanceThreshold)
+	}
+
+	return nil
+}
+
+func resourceVSphereDatastoreClusterUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	pod := object.NewStoragePod(client.Client, datastoreClusterReference(d.Id()))
+
+	if d.HasChange("name") {
+		task, err := pod.Rename(context.TODO(), d.Get("name").(string))
+		if err != nil {
+			return fmt.Errorf("error renaming datastore cluster %s: %s", d.Id(), err)
+		}
+		if err := task.Wait(context.TODO()); err != nil {
+			return fmt.Errorf("error renaming datastore cluster %s: %s", d.Id(), err)
+		}
+	}
+
+	if err := datastoreClusterConfigure(client, pod, d); err != nil {
+		return err
+	}
+
+	if d.HasChange("datastore_ids") {
+		o, n := d.GetChange("datastore_ids")
+		oldIDs := o.(*schema.Set)
+		newIDs := n.(*schema.Set)
+
+		if err := datastoreClusterMoveInto(pod.Folder, newIDs.Difference(oldIDs).List()); err != nil {
+			return err
+		}
+		if err := datastoreClusterMoveOut(client, d, oldIDs.Difference(newIDs).List()); err != nil {
+			return err
+		}
+	}
+
+	return resourceVSphereDatastoreClusterRead(d, meta)
+}
+
+func resourceVSphereDatastoreClusterDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	pod := object.NewStoragePod(client.Client, datastoreClusterReference(d.Id()))
+
+	// Move the member datastores out first so they are not lost with the
+	// datastore cluster.
+	if err := datastoreClusterMoveOut(client, d, d.Get("datastore_ids").(*schema.Set).List()); err != nil {
+		return err
+	}
+
+	task, err := pod.Destroy(context.TODO())
+	if err != nil {
+		return fmt.Errorf("error destroying datastore cluster %s: %s", d.Id(), err)
+	}
+	if err := task.Wait(context.TODO()); err != nil {
+		return fmt.Errorf("error destroying datastore cluster %s: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceVSphereDatastoreClusterImport imports a datastore cluster by its
+// inventory path, such as "/dc1/datastore/pod1".
+func resourceVSphereDatastoreClusterImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*govmomi.Client)
+
+	ref, err := object.NewSearchIndex(client.Client).FindByInventoryPath(context.TODO(), d.Id())
+	if err != nil {
+		return nil, err
+	}
+	if ref == nil || ref.Reference().Type != "StoragePod" {
+		return nil, fmt.Errorf("%s is not a datastore cluster", d.Id())
+	}
+
+	dcPath, err := datacenterInventoryPath(client, ref.Reference())
+	if err != nil {
+		return nil, err
+	}
+	folder, _, err := splitDatacenterInventoryPath(dcPath, "datastore", d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("datacenter", strings.TrimPrefix(dcPath, "/"))
+	d.Set("folder", folder)
+	d.SetId(ref.Reference().Value)
+	return []*schema.ResourceData{d}, nil
+}
+
+func datastoreClusterReference(id string) types.ManagedObjectReference {
+	return types.ManagedObjectReference{
+		Type:  "StoragePod",
+		Value: id,
+	}
+}
+
+// datastoreClusterConfigure applies the Storage DRS settings of a datastore
+// cluster.
+func datastoreClusterConfigure(client *govmomi.Client, pod *object.StoragePod, d *schema.ResourceData) error {
+	spec := types.StorageDrsConfigSpec{
+		PodConfigSpec: &types.StorageDrsPodConfigSpec{
+			Enabled:                types.NewBool(d.Get("sdrs_enabled").(bool)),
+			DefaultVmBehavior:      d.Get("sdrs_automation_level").(string),
+			LoadBalanceInterval:    int32(d.Get("sdrs_load_balance_interval").(int)),
+			DefaultIntraVmAffinity: types.NewBool(d.Get("sdrs_default_intra_vm_affinity").(bool)),
+			SpaceLoadBalanceConfig: &types.StorageDrsSpaceLoadBalanceConfig{
+				SpaceThresholdMode:            d.Get("sdrs_space_threshold_mode").(string),
+				SpaceUtilizationThreshold:     int32(d.Get("sdrs_space_utilization_threshold").(int)),
+				FreeSpaceThresholdGB:          int32(d.Get("sdrs_free_space_threshold").(int)),
+				MinSpaceUtilizationDifference: int32(d.Get("sdrs_space_utilization_difference").(int)),
+			},
+			IoLoadBalanceEnabled: types.NewBool(d.Get("sdrs_io_load_balance_enabled").(bool)),
+			IoLoadBalanceConfig: &types.StorageDrsIoLoadBalanceConfig{
+				IoLatencyThreshold:       int32(d.Get("sdrs_io_latency_threshold").(int)),
+				IoLoadImbalanceThreshold: int32(d.Get("sdrs_io_load_imbalance_threshold").(int)),
+			},
+		},
+	}
+
+	srm := object.NewStorageResourceManager(client.Client)
+	task, err := srm.ConfigureStorageDrsForPod(context.TODO(), pod, spec, true)
+	if err != nil {
+		return fmt.Errorf("error configuring Storage DRS for datastore cluster %s: %s", pod.Reference().Value, err)
+	}
+	if err := task.Wait(context.TODO()); err != nil {
+		return fmt.Errorf("error configuring Storage DRS for datastore cluster %s: %s", pod.Reference().Value, err)
+	}
+	return nil
+}
+
+// datastoreClusterMoveInto moves datastores into a folder, which is either a
+// datastore cluster or a plain datastore folder.
+func datastoreClusterMoveInto(f *object.Folder, ids []interface{}) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	refs := make([]types.ManagedObjectReference, 0, len(ids))
+	for _, id := range ids {
+		refs = append(refs, datastoreReference(id.(string)))
+	}
+
+	task, err := f.MoveInto(context.TODO(), refs)
+	if err != nil {
+		return fmt.Errorf("error moving datastores into %s: %s", f.Reference().Value, err)
+	}
+	if err := task.Wait(context.TODO()); err != nil {
+		return fmt.Errorf("error moving datastores into %s: %s", f.Reference().Value, err)
+	}
+	return nil
+}
+
+// datastoreClusterMoveOut moves datastores out of a datastore cluster, into
+// the datastore folder of its datacenter.
+func datastoreClusterMoveOut(client *govmomi.Client, d *schema.ResourceData, ids []interface{}) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	dc, err := getDatacenter(client, d.Get("datacenter").(string))
+	if err != nil {
+		return fmt.Errorf("error finding datacenter: %s", err)
+	}
+	dcFolders, err := dc.Folders(context.TODO())
+	if err != nil {
+		return err
+	}
+	return datastoreClusterMoveInto(dcFolders.DatastoreFolder, ids)
+}
@@ -0,0 +1,142 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/mo"
+	"golang.org/x/net/context"
+)
+
+func TestAccVSphereDatastoreCluster_basic(t *testing.T) {
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+	datastore := os.Getenv("VSPHERE_DATASTORE")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if datastore == "" {
+				t.Fatal("env variable VSPHERE_DATASTORE must be set for acceptance tests")
+			}
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereDatastoreClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereDatastoreClusterConfig, datacenter),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereDatastoreClusterExists("vsphere_datastore_cluster.pod"),
+					resource.TestCheckResourceAttr("vsphere_datastore_cluster.pod", "sdrs_enabled", "true"),
+					resource.TestCheckResourceAttr("vsphere_datastore_cluster.pod", "sdrs_automation_level", "manual"),
+					resource.TestCheckResourceAttr("vsphere_datastore_cluster.pod", "datastore_ids.#", "0"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereDatastoreClusterConfigUpdated, datacenter, datastore, datacenter),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereDatastoreClusterExists("vsphere_datastore_cluster.pod"),
+					resource.TestCheckResourceAttr("vsphere_datastore_cluster.pod", "name", "terraform-test-pod-renamed"),
+					resource.TestCheckResourceAttr("vsphere_datastore_cluster.pod", "sdrs_automation_level", "automated"),
+					resource.TestCheckResourceAttr("vsphere_datastore_cluster.pod", "sdrs_space_utilization_threshold", "70"),
+					resource.TestCheckResourceAttr("vsphere_datastore_cluster.pod", "sdrs_io_latency_threshold", "20"),
+					resource.TestCheckResourceAttr("vsphere_datastore_cluster.pod", "sdrs_default_intra_vm_affinity", "false"),
+					resource.TestCheckResourceAttr("vsphere_datastore_cluster.pod", "datastore_ids.#", "1"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereDatastoreClusterConfig, datacenter),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereDatastoreClusterExists("vsphere_datastore_cluster.pod"),
+					resource.TestCheckResourceAttr("vsphere_datastore_cluster.pod", "datastore_ids.#", "0"),
+				),
+			},
+			{
+				ResourceName:      "vsphere_datastore_cluster.pod",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("/%s/datastore/terraform-test-pod", datacenter),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckVSphereDatastoreClusterDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*govmomi.Client)
+	collector := property.DefaultCollector(client.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "vsphere_datastore_cluster" {
+			continue
+		}
+
+		var msp mo.StoragePod
+		err := collector.RetrieveOne(context.TODO(), datastoreClusterReference(rs.Primary.ID), []string{"name"}, &msp)
+		if err == nil {
+			return fmt.Errorf("datastore cluster %s still exists", rs.Primary.ID)
+		}
+		if !isManagedObjectNotFoundError(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckVSphereDatastoreClusterExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*govmomi.Client)
+		collector := property.DefaultCollector(client.Client)
+
+		var msp mo.StoragePod
+		if err := collector.RetrieveOne(context.TODO(), datastoreClusterReference(rs.Primary.ID), []string{"name"}, &msp); err != nil {
+			return fmt.Errorf("error finding datastore cluster %s: %s", rs.Primary.ID, err)
+		}
+		if msp.Name != rs.Primary.Attributes["name"] {
+			return fmt.Errorf("expected datastore cluster name %s, got %s", rs.Primary.Attributes["name"], msp.Name)
+		}
+
+		return nil
+	}
+}
+
+const testAccCheckVSphereDatastoreClusterConfig = `
+resource "vsphere_datastore_cluster" "pod" {
+  name       = "terraform-test-pod"
+  datacenter = "%s"
+
+  sdrs_enabled = true
+}
+`
+
+const testAccCheckVSphereDatastoreClusterConfigUpdated = `
+data "vsphere_datastore" "ds" {
+  datacenter = "%s"
+  name       = "%s"
+}
+
+resource "vsphere_datastore_cluster" "pod" {
+  name          = "terraform-test-pod-renamed"
+  datacenter    = "%s"
+  datastore_ids = ["${data.vsphere_datastore.ds.id}"]
+
+  sdrs_enabled                     = true
+  sdrs_automation_level            = "automated"
+  sdrs_space_utilization_threshold = 70
+  sdrs_io_latency_threshold        = 20
+  sdrs_default_intra_vm_affinity   = false
+}
+`
@@ -0,0 +1,482 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+var distributedPortGroupTypes = []string{
+	string(types.DistributedVirtualPortgroupPortgroupTypeEarlyBinding),
+	string(types.DistributedVirtualPortgroupPortgroupTypeLateBinding),
+	string(types.DistributedVirtualPortgroupPortgroupTypeEphemeral),
+}
+
+var distributedPortGroupTeamingPolicies = []string{
+	"loadbalance_ip",
+	"loadbalance_srcmac",
+	"loadbalance_srcid",
+	"loadbalance_loadbased",
+	"failover_explicit",
+}
+
+func resourceVSphereDistributedPortGroup() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"name": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+		},
+
+		"distributed_virtual_switch_id": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+
+		"description": &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+
+		"type": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      string(types.DistributedVirtualPortgroupPortgroupTypeEarlyBinding),
+			ValidateFunc: validateStringInSlice(distributedPortGroupTypes),
+		},
+
+		"number_of_ports": &schema.Schema{
+			Type:     schema.TypeInt,
+			Optional: true,
+			Computed: true,
+		},
+
+		"auto_expand": &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+
+		"vlan_id": &schema.Schema{
+			Type:          schema.TypeInt,
+			Optional:      true,
+			ConflictsWith: []string{"vlan_range", "port_private_secondary_vlan_id"},
+		},
+
+		"vlan_range": &schema.Schema{
+			Type:          schema.TypeList,
+			Optional:      true,
+			ConflictsWith: []string{"vlan_id", "port_private_secondary_vlan_id"},
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"min_vlan": &schema.Schema{
+						Type:     schema.TypeInt,
+						Required: true,
+					},
+
+					"max_vlan": &schema.Schema{
+						Type:     schema.TypeInt,
+						Required: true,
+					},
+				},
+			},
+		},
+
+		"port_private_secondary_vlan_id": &schema.Schema{
+			Type:          schema.TypeInt,
+			Optional:      true,
+			ConflictsWith: []string{"vlan_id", "vlan_range"},
+		},
+
+		"allow_promiscuous": &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+
+		"allow_forged_transmits": &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+
+		"allow_mac_changes": &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+
+		"teaming_policy": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validateStringInSlice(distributedPortGroupTeamingPolicies),
+		},
+
+		"notify_switches": &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+
+		"failback": &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+
+		"active_uplinks": &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+
+		"standby_uplinks": &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+
+		"key": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+	for k, v := range trafficShapingSchema("ingress") {
+		s[k] = v
+	}
+	for k, v := range trafficShapingSchema("egress") {
+		s[k] = v
+	}
+
+	return &schema.Resource{
+		Create: resourceVSphereDistributedPortGroupCreate,
+		Read:   resourceVSphereDistributedPortGroupRead,
+		Update: resourceVSphereDistributedPortGroupUpdate,
+		Delete: resourceVSphereDistributedPortGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereDistributedPortGroupImport,
+		},
+
+		Schema: s,
+	}
+}
+
+// trafficShapingSchema returns the traffic shaping arguments for one
+// direction of traffic, prefixed with the direction. Bandwidths are in bits
+// per second and burst sizes in bytes.
+func trafficShapingSchema(prefix string) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		prefix + "_shaping_enabled": &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+
+		prefix + "_shaping_average_bandwidth": &schema.Schema{
+			Type:     schema.TypeInt,
+			Optional: true,
+			Computed: true,
+		},
+
+		prefix + "_shaping_peak_bandwidth": &schema.Schema{
+			Type:     schema.TypeInt,
+			Optional: true,
+			Computed: true,
+		},
+
+		prefix + "_shaping_burst_size": &schema.Schema{
+			Type:     schema.TypeInt,
+			Optional: true,
+			Computed: true,
+		},
+	}
+}
+
+func resourceVSphereDistributedPortGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	req := &types.CreateDVPortgroup_Task{
+		This: distributedVirtualSwitchReference(d.Get("distributed_virtual_switch_id").(string)),
+		Spec: distributedPortGroupConfigSpec(d),
+	}
+	res, err := methods.CreateDVPortgroup_Task(context.TODO(), client, req)
+	if err != nil {
+		return fmt.Errorf("error creating distributed port group %s: %s", req.Spec.Name, err)
+	}
+	info, err := object.NewTask(client.Client, res.Returnval).WaitForResult(context.TODO(), nil)
+	if err != nil {
+		return fmt.Errorf("error creating distributed port group %s: %s", req.Spec.Name, err)
+	}
+	ref := info.Result.(types.ManagedObjectReference)
+	log.Printf("[INFO] Created distributed port group: %s", ref.Value)
+
+	d.SetId(ref.Value)
+
+	return resourceVSphereDistributedPortGroupRead(d, meta)
+}
+
+func resourceVSphereDistributedPortGroupRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	mpg, err := distributedPortGroupProperties(client, d.Id())
+	if err != nil {
+		if isManagedObjectNotFoundError(err) {
+			log.Printf("[DEBUG] distributed port group %s not found: %s", d.Id(), err)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	config := mpg.Config
+
+	d.Set("key", mpg.Key)
+	d.Set("name", config.Name)
+	d.Set("description", config.Description)
+	d.Set("type", config.Type)
+	d.Set("number_of_ports", config.NumPorts)
+	if config.AutoExpand != nil {
+		d.Set("auto_expand", *config.AutoExpand)
+	}
+	if config.DistributedVirtualSwitch != nil {
+		d.Set("distributed_virtual_switch_id", config.DistributedVirtualSwitch.Value)
+	}
+
+	setting, ok := config.DefaultPortConfig.(*types.VMwareDVSPortSetting)
+	if !ok {
+		return fmt.Errorf("unexpected port configuration type %T for distributed port group %s", config.DefaultPortConfig, d.Id())
+	}
+
+	d.Set("vlan_id", 0)
+	d.Set("port_private_secondary_vlan_id", 0)
+	var vlanRanges []map[string]interface{}
+	switch vlan := setting.Vlan.(type) {
+	case *types.VmwareDistributedVirtualSwitchVlanIdSpec:
+		d.Set("vlan_id", vlan.VlanId)
+	case *types.VmwareDistributedVirtualSwitchTrunkVlanSpec:
+		for _, r := range vlan.VlanId {
+			vlanRanges = append(vlanRanges, map[string]interface{}{
+				"min_vlan": r.Start,
+				"max_vlan": r.End,
+			})
+		}
+	case *types.VmwareDistributedVirtualSwitchPvlanSpec:
+		d.Set("port_private_secondary_vlan_id", vlan.PvlanId)
+	}
+	if err := d.Set("vlan_range", vlanRanges); err != nil {
+		return fmt.Errorf("Invalid VLAN ranges to set: %#v", vlanRanges)
+	}
+
+	if sec := setting.SecurityPolicy; sec != nil {
+		setBoolPolicy(d, "allow_promiscuous", sec.AllowPromiscuous)
+		setBoolPolicy(d, "allow_forged_transmits", sec.ForgedTransmits)
+		setBoolPolicy(d, "allow_mac_changes", sec.MacChanges)
+	}
+
+	if teaming := setting.UplinkTeamingPolicy; teaming != nil {
+		if teaming.Policy != nil {
+			d.Set("teaming_policy", teaming.Policy.Value)
+		}
+		setBoolPolicy(d, "notify_switches", teaming.NotifySwitches)
+		if teaming.RollingOrder != nil && teaming.RollingOrder.Value != nil {
+			d.Set("failback", !*teaming.RollingOrder.Value)
+		}
+		if order := teaming.UplinkPortOrder; order != nil {
+			d.Set("active_uplinks", order.ActiveUplinkPort)
+			d.Set("standby_uplinks", order.StandbyUplinkPort)
+		}
+	}
+
+	flattenTrafficShaping(d, "ingress", setting.InShapingPolicy)
+	flattenTrafficShaping(d, "egress", setting.OutShapingPolicy)
+
+	return nil
+}
+
+func resourceVSphereDistributedPortGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	mpg, err := distributedPortGroupProperties(client, d.Id())
+	if err != nil {
+		return err
+	}
+
+	spec := distributedPortGroupConfigSpec(d)
+	spec.ConfigVersion = mpg.Config.ConfigVersion
+
+	pg := object.NewDistributedVirtualPortgroup(client.Client, distributedPortGroupReference(d.Id()))
+	task, err := pg.Reconfigure(context.TODO(), spec)
+	if err != nil {
+		return fmt.Errorf("error reconfiguring distributed port group %s: %s", d.Id(), err)
+	}
+	if err := task.Wait(context.TODO()); err != nil {
+		return fmt.Errorf("error reconfiguring distributed port group %s: %s", d.Id(), err)
+	}
+
+	return resourceVSphereDistributedPortGroupRead(d, meta)
+}
+
+func resourceVSphereDistributedPortGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	pg := object.NewDistributedVirtualPortgroup(client.Client, distributedPortGroupReference(d.Id()))
+
+	task, err := pg.Destroy(context.TODO())
+	if err != nil {
+		return fmt.Errorf("error destroying distributed port group %s: %s", d.Id(), err)
+	}
+	if err := task.Wait(context.TODO()); err != nil {
+		return fmt.Errorf("error destroying distributed port group %s: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceVSphereDistributedPortGroupImport imports a distributed port group
+// by its inventory path, such as "/dc1/network/pg-web".
+func resourceVSphereDistributedPortGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*govmomi.Client)
+
+	ref, err := object.NewSearchIndex(client.Client).FindByInventoryPath(context.TODO(), d.Id())
+	if err != nil {
+		return nil, err
+	}
+	pg, ok := ref.(*object.DistributedVirtualPortgroup)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a distributed port group", d.Id())
+	}
+
+	d.SetId(pg.Reference().Value)
+	return []*schema.ResourceData{d}, nil
+}
+
+func distributedPortGroupReference(id string) types.ManagedObjectReference {
+	return types.ManagedObjectReference{
+		Type:  "DistributedVirtualPortgroup",
+		Value: id,
+	}
+}
+
+func distributedPortGroupProperties(client *govmomi.Client, id string) (*mo.DistributedVirtualPortgroup, error) {
+	var mpg mo.DistributedVirtualPortgroup
+	collector := property.DefaultCollector(client.Client)
+	if err := collector.RetrieveOne(context.TODO(), distributedPortGroupReference(id), []string{"key", "config"}, &mpg); err != nil {
+		return nil, err
+	}
+	return &mpg, nil
+}
+
+func distributedPortGroupConfigSpec(d *schema.ResourceData) types.DVPortgroupConfigSpec {
+	setting := &types.VMwareDVSPortSetting{
+		SecurityPolicy: &types.DVSSecurityPolicy{
+			AllowPromiscuous: &types.BoolPolicy{Value: types.NewBool(d.Get("allow_promiscuous").(bool))},
+			ForgedTransmits:  &types.BoolPolicy{Value: types.NewBool(d.Get("allow_forged_transmits").(bool))},
+			MacChanges:       &types.BoolPolicy{Value: types.NewBool(d.Get("allow_mac_changes").(bool))},
+		},
+		UplinkTeamingPolicy: &types.VmwareUplinkPortTeamingPolicy{
+			NotifySwitches: &types.BoolPolicy{Value: types.NewBool(d.Get("notify_switches").(bool))},
+			RollingOrder:   &types.BoolPolicy{Value: types.NewBool(!d.Get("failback").(bool))},
+		},
+	}
+	setting.InShapingPolicy = expandTrafficShaping(d, "ingress")
+	setting.OutShapingPolicy = expandTrafficShaping(d, "egress")
+
+	if v, ok := d.GetOk("teaming_policy"); ok {
+		setting.UplinkTeamingPolicy.Policy = &types.StringPolicy{Value: v.(string)}
+	}
+	active := stringList(d.Get("active_uplinks").([]interface{}))
+	standby := stringList(d.Get("standby_uplinks").([]interface{}))
+	if len(active) > 0 || len(standby) > 0 {
+		setting.UplinkTeamingPolicy.UplinkPortOrder = &types.VMwareUplinkPortOrderPolicy{
+			ActiveUplinkPort:  active,
+			StandbyUplinkPort: standby,
+		}
+	}
+
+	switch {
+	case len(d.Get("vlan_range").([]interface{})) > 0:
+		trunk := &types.VmwareDistributedVirtualSwitchTrunkVlanSpec{}
+		for _, v := range d.Get("vlan_range").([]interface{}) {
+			r := v.(map[string]interface{})
+			trunk.VlanId = append(trunk.VlanId, types.NumericRange{
+				Start: int32(r["min_vlan"].(int)),
+				End:   int32(r["max_vlan"].(int)),
+			})
+		}
+		setting.Vlan = trunk
+	case d.Get("port_private_secondary_vlan_id").(int) > 0:
+		setting.Vlan = &types.VmwareDistributedVirtualSwitchPvlanSpec{
+			PvlanId: int32(d.Get("port_private_secondary_vlan_id").(int)),
+		}
+	default:
+		setting.Vlan = &types.VmwareDistributedVirtualSwitchVlanIdSpec{
+			VlanId: int32(d.Get("vlan_id").(int)),
+		}
+	}
+
+	return types.DVPortgroupConfigSpec{
+		Name:              d.Get("name").(string),
+		Description:       d.Get("description").(string),
+		Type:              d.Get("type").(string),
+		NumPorts:          int32(d.Get("number_of_ports").(int)),
+		AutoExpand:        types.NewBool(d.Get("auto_expand").(bool)),
+		DefaultPortConfig: setting,
+	}
+}
+
+func expandTrafficShaping(d *schema.ResourceData, prefix string) *types.DVSTrafficShapingPolicy {
+	policy := &types.DVSTrafficShapingPolicy{
+		Enabled: &types.BoolPolicy{Value: types.NewBool(d.Get(prefix + "_shaping_enabled").(bool))},
+	}
+	if v, ok := d.GetOk(prefix + "_shaping_average_bandwidth"); ok {
+		policy.AverageBandwidth = &types.LongPolicy{Value: int64(v.(int))}
+	}
+	if v, ok := d.GetOk(prefix + "_shaping_peak_bandwidth"); ok {
+		policy.PeakBandwidth = &types.LongPolicy{Value: int64(v.(int))}
+	}
+	if v, ok := d.GetOk(prefix + "_shaping_burst_size"); ok {
+		policy.BurstSize = &types.LongPolicy{Value: int64(v.(int))}
+	}
+	return policy
+}
+
+func flattenTrafficShaping(d *schema.ResourceData, prefix string, policy *types.DVSTrafficShapingPolicy) {
+	if policy == nil {
+		return
+	}
+	setBoolPolicy(d, prefix+"_shaping_enabled", policy.Enabled)
+	if policy.AverageBandwidth != nil {
+		d.Set(prefix+"_shaping_average_bandwidth", policy.AverageBandwidth.Value)
+	}
+	if policy.PeakBandwidth != nil {
+		d.Set(prefix+"_shaping_peak_bandwidth", policy.PeakBandwidth.Value)
+	}
+	if policy.BurstSize != nil {
+		d.Set(prefix+"_shaping_burst_size", policy.BurstSize.Value)
+	}
+}
+
+// setBoolPolicy sets key from a BoolPolicy, if the policy has a value.
+func setBoolPolicy(d *schema.ResourceData, key string, policy *types.BoolPolicy) {
+	if policy != nil && policy.Value != nil {
+		d.Set(key, *policy.Value)
+	}
+}
+
+func stringList(l []interface{}) []string {
+	var s []string
+	for _, v := range l {
+		s = append(s, v.(string))
+	}
+	return s
+}
@@ -0,0 +1,149 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/vmware/govmomi"
+)
+
+func TestAccVSphereDistributedPortGroup_basic(t *testing.T) {
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereDistributedPortGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereDistributedPortGroupConfig, datacenter),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereDistributedPortGroupExists("vsphere_distributed_port_group.pg"),
+					resource.TestCheckResourceAttrPair("vsphere_distributed_port_group.pg", "distributed_virtual_switch_id", "vsphere_distributed_virtual_switch.dvs", "id"),
+					resource.TestCheckResourceAttr("vsphere_distributed_port_group.pg", "vlan_id", "1000"),
+					resource.TestCheckResourceAttr("vsphere_distributed_port_group.pg", "number_of_ports", "16"),
+					resource.TestCheckResourceAttr("vsphere_distributed_port_group.pg", "allow_promiscuous", "false"),
+					resource.TestCheckResourceAttr("vsphere_distributed_port_group.pg", "active_uplinks.#", "1"),
+					resource.TestCheckResourceAttr("vsphere_distributed_port_group.pg", "standby_uplinks.#", "1"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereDistributedPortGroupConfigUpdated, datacenter),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereDistributedPortGroupExists("vsphere_distributed_port_group.pg"),
+					resource.TestCheckResourceAttr("vsphere_distributed_port_group.pg", "vlan_id", "0"),
+					resource.TestCheckResourceAttr("vsphere_distributed_port_group.pg", "vlan_range.#", "2"),
+					resource.TestCheckResourceAttr("vsphere_distributed_port_group.pg", "vlan_range.1.min_vlan", "200"),
+					resource.TestCheckResourceAttr("vsphere_distributed_port_group.pg", "type", "ephemeral"),
+					resource.TestCheckResourceAttr("vsphere_distributed_port_group.pg", "allow_promiscuous", "true"),
+					resource.TestCheckResourceAttr("vsphere_distributed_port_group.pg", "teaming_policy", "loadbalance_srcmac"),
+					resource.TestCheckResourceAttr("vsphere_distributed_port_group.pg", "egress_shaping_enabled", "true"),
+					resource.TestCheckResourceAttr("vsphere_distributed_port_group.pg", "egress_shaping_average_bandwidth", "100000000"),
+				),
+			},
+			{
+				ResourceName:      "vsphere_distributed_port_group.pg",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("/%s/network/terraform-test-pg", datacenter),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckVSphereDistributedPortGroupDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*govmomi.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "vsphere_distributed_port_group" {
+			continue
+		}
+
+		_, err := distributedPortGroupProperties(client, rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("distributed port group %s still exists", rs.Primary.ID)
+		}
+		if !isManagedObjectNotFoundError(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckVSphereDistributedPortGroupExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*govmomi.Client)
+		mpg, err := distributedPortGroupProperties(client, rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("error finding distributed port group %s: %s", rs.Primary.ID, err)
+		}
+		if mpg.Config.Name != rs.Primary.Attributes["name"] {
+			return fmt.Errorf("expected distributed port group name %s, got %s", rs.Primary.Attributes["name"], mpg.Config.Name)
+		}
+
+		return nil
+	}
+}
+
+const testAccCheckVSphereDistributedPortGroupConfig = `
+resource "vsphere_distributed_virtual_switch" "dvs" {
+  name       = "terraform-test-dvs"
+  datacenter = "%s"
+  uplinks    = ["uplink-a", "uplink-b"]
+}
+
+resource "vsphere_distributed_port_group" "pg" {
+  name                          = "terraform-test-pg"
+  distributed_virtual_switch_id = "${vsphere_distributed_virtual_switch.dvs.id}"
+  vlan_id                       = 1000
+  number_of_ports               = 16
+  active_uplinks                = ["uplink-a"]
+  standby_uplinks               = ["uplink-b"]
+}
+`
+
+const testAccCheckVSphereDistributedPortGroupConfigUpdated = `
+resource "vsphere_distributed_virtual_switch" "dvs" {
+  name       = "terraform-test-dvs"
+  datacenter = "%s"
+  uplinks    = ["uplink-a", "uplink-b"]
+}
+
+resource "vsphere_distributed_port_group" "pg" {
+  name                          = "terraform-test-pg"
+  distributed_virtual_switch_id = "${vsphere_distributed_virtual_switch.dvs.id}"
+  type                          = "ephemeral"
+  allow_promiscuous             = true
+  teaming_policy                = "loadbalance_srcmac"
+  active_uplinks                = ["uplink-a", "uplink-b"]
+  standby_uplinks               = []
+
+  vlan_range {
+    min_vlan = 100
+    max_vlan = 199
+  }
+
+  vlan_range {
+    min_vlan = 200
+    max_vlan = 299
+  }
+
+  egress_shaping_enabled           = true
+  egress_shaping_average_bandwidth = 100000000
+  egress_shaping_peak_bandwidth    = 200000000
+  egress_shaping_burst_size        = 104857600
+}
+`
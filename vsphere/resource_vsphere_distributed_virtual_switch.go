@@ -0,0 +1,470 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+var lacpAPIVersions = []string{
+	string(types.VMwareDvsLacpApiVersionSingleLag),
+	string(types.VMwareDvsLacpApiVersionMultipleLag),
+}
+
+var lacpModes = []string{
+	string(types.VMwareUplinkLacpModeActive),
+	string(types.VMwareUplinkLacpModePassive),
+}
+
+var networkResourceControlVersions = []string{
+	string(types.DistributedVirtualSwitchNetworkResourceControlVersionVersion2),
+	string(types.DistributedVirtualSwitchNetworkResourceControlVersionVersion3),
+}
+
+func resourceVSphereDistributedVirtualSwitch() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereDistributedVirtualSwitchCreate,
+		Read:   resourceVSphereDistributedVirtualSwitchRead,
+		Update: resourceVSphereDistributedVirtualSwitchUpdate,
+		Delete: resourceVSphereDistributedVirtualSwitchDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereDistributedVirtualSwitchImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"datacenter": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"folder": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"version": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"uplinks": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"max_mtu": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+
+			"lacp_api_version": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateStringInSlice(lacpAPIVersions),
+			},
+
+			"lacp_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+			},
+
+			"lacp_mode": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateStringInSlice(lacpModes),
+			},
+
+			"network_resource_control_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"network_resource_control_version": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateStringInSlice(networkResourceControlVersions),
+			},
+
+			"host": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host_system_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+
+						"devices": &schema.Schema{
+							Type:     schema.TypeList,
+							Required: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+
+			"uuid": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceVSphereDistributedVirtualSwitchCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	dc, err := getDatacenter(client, d.Get("datacenter").(string))
+	if err != nil {
+		return fmt.Errorf("error finding datacenter: %s", err)
+	}
+
+	var f *object.Folder
+	if v, ok := d.GetOk("folder"); ok {
+		finder := find.NewFinder(client.Client, true)
+		f, err = finder.Folder(context.TODO(), path.Join(dc.InventoryPath, "network", v.(string)))
+		if err != nil {
+			return fmt.Errorf("failed to find folder that will contain the distributed virtual switch: %s", err)
+		}
+	} else {
+		dcFolders, err := dc.Folders(context.TODO())
+		if err != nil {
+			return err
+		}
+		f = dcFolders.NetworkFolder
+	}
+
+	spec := distributedVirtualSwitchConfigSpec(d)
+	for _, host := range d.Get("host").(*schema.Set).List() {
+		spec.Host = append(spec.Host, distributedVirtualSwitchHostMemberConfigSpec(types.ConfigSpecOperationAdd, host.(map[string]interface{})))
+	}
+	createSpec := types.DVSCreateSpec{
+		ConfigSpec: spec,
+	}
+	if v, ok := d.GetOk("version"); ok {
+		createSpec.ProductInfo = &types.DistributedVirtualSwitchProductSpec{
+			Version: v.(string),
+		}
+	}
+
+	task, err := f.CreateDVS(context.TODO(), createSpec)
+	if err != nil {
+		return fmt.Errorf("error creating distributed virtual switch %s: %s", spec.Name, err)
+	}
+	info, err := task.WaitForResult(context.TODO(), nil)
+	if err != nil {
+		return fmt.Errorf("error creating distributed virtual switch %s: %s", spec.Name, err)
+	}
+	ref := info.Result.(types.ManagedObjectReference)
+	log.Printf("[INFO] Created distributed virtual switch: %s", ref.Value)
+
+	d.SetId(ref.Value)
+
+	if d.Get("network_resource_control_enabled").(bool) {
+		if err := distributedVirtualSwitchEnableNetworkResourceControl(client, ref, true); err != nil {
+			return err
+		}
+	}
+
+	return resourceVSphereDistributedVirtualSwitchRead(d, meta)
+}
+
+func resourceVSphereDistributedVirtualSwitchRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	mdvs, err := distributedVirtualSwitchProperties(client, d.Id())
+	if err != nil {
+		if isManagedObjectNotFoundError(err) {
+			log.Printf("[DEBUG] distributed virtual switch %s not found: %s", d.Id(), err)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	config, ok := mdvs.Config.(*types.VMwareDVSConfigInfo)
+	if !ok {
+		return fmt.Errorf("unexpected configuration type %T for distributed virtual switch %s", mdvs.Config, d.Id())
+	}
+
+	d.Set("name", config.Name)
+	d.Set("uuid", config.Uuid)
+	d.Set("description", config.Description)
+	d.Set("version", config.ProductInfo.Version)
+	d.Set("max_mtu", config.MaxMtu)
+	d.Set("lacp_api_version", config.LacpApiVersion)
+	d.Set("network_resource_control_version", config.NetworkResourceControlVersion)
+	if config.NetworkResourceManagementEnabled != nil {
+		d.Set("network_resource_control_enabled", *config.NetworkResourceManagementEnabled)
+	}
+
+	if policy, ok := config.UplinkPortPolicy.(*types.DVSNameArrayUplinkPortPolicy); ok {
+		d.Set("uplinks", policy.UplinkPortName)
+	}
+
+	if setting, ok := config.DefaultPortConfig.(*types.VMwareDVSPortSetting); ok && setting.LacpPolicy != nil {
+		if setting.LacpPolicy.Enable != nil && setting.LacpPolicy.Enable.Value != nil {
+			d.Set("lacp_enabled", *setting.LacpPolicy.Enable.Value)
+		}
+		if setting.LacpPolicy.Mode != nil {
+			d.Set("lacp_mode", setting.LacpPolicy.Mode.Value)
+		}
+	}
+
+	var hosts []interface{}
+	for _, member := range config.Host {
+		if member.Config.Host == nil {
+			continue
+		}
+		devices := make([]string, 0)
+		if backing, ok := member.Config.Backing.(*types.DistributedVirtualSwitchHostMemberPnicBacking); ok {
+			for _, pnic := range backing.PnicSpec {
+				devices = append(devices, pnic.PnicDevice)
+			}
+		}
+		hosts = append(hosts, map[string]interface{}{
+			"host_system_id": member.Config.Host.Value,
+			"devices":        devices,
+		})
+	}
+	if err := d.Set("host", hosts); err != nil {
+		return fmt.Errorf("Invalid hosts to set: %#v", hosts)
+	}
+
+	return nil
+}
+
+func resourceVSphereDistributedVirtualSwitchUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	ref := distributedVirtualSwitchReference(d.Id())
+
+	// Upgrades change the configuration version, so they are applied before
+	// the rest of the configuration.
+	if d.HasChange("version") {
+		req := &types.PerformDvsProductSpecOperation_Task{
+			This:      ref,
+			Operation: string(types.DistributedVirtualSwitchProductSpecOperationTypeUpgrade),
+			ProductSpec: &types.DistributedVirtualSwitchProductSpec{
+				Version: d.Get("version").(string),
+			},
+		}
+		res, err := methods.PerformDvsProductSpecOperation_Task(context.TODO(), client, req)
+		if err != nil {
+			return fmt.Errorf("error upgrading distributed virtual switch %s: %s", d.Id(), err)
+		}
+		if err := object.NewTask(client.Client, res.Returnval).Wait(context.TODO()); err != nil {
+			return fmt.Errorf("error upgrading distributed virtual switch %s: %s", d.Id(), err)
+		}
+	}
+
+	mdvs, err := distributedVirtualSwitchProperties(client, d.Id())
+	if err != nil {
+		return err
+	}
+
+	spec := distributedVirtualSwitchConfigSpec(d)
+	spec.ConfigVersion = mdvs.Config.GetDVSConfigInfo().ConfigVersion
+	if d.HasChange("host") {
+		o, n := d.GetChange("host")
+		oldHosts := make(map[string]map[string]interface{})
+		for _, v := range o.(*schema.Set).List() {
+			host := v.(map[string]interface{})
+			oldHosts[host["host_system_id"].(string)] = host
+		}
+		newHosts := make(map[string]bool)
+		for _, v := range n.(*schema.Set).List() {
+			host := v.(map[string]interface{})
+			newHosts[host["host_system_id"].(string)] = true
+			op := types.ConfigSpecOperationAdd
+			if _, ok := oldHosts[host["host_system_id"].(string)]; ok {
+				op = types.ConfigSpecOperationEdit
+			}
+			spec.Host = append(spec.Host, distributedVirtualSwitchHostMemberConfigSpec(op, host))
+		}
+		for id, host := range oldHosts {
+			if !newHosts[id] {
+				spec.Host = append(spec.Host, distributedVirtualSwitchHostMemberConfigSpec(types.ConfigSpecOperationRemove, host))
+			}
+		}
+	}
+
+	dvs := object.NewDistributedVirtualSwitch(client.Client, ref)
+	task, err := dvs.Reconfigure(context.TODO(), spec)
+	if err != nil {
+		return fmt.Errorf("error reconfiguring distributed virtual switch %s: %s", d.Id(), err)
+	}
+	if err := task.Wait(context.TODO()); err != nil {
+		return fmt.Errorf("error reconfiguring distributed virtual switch %s: %s", d.Id(), err)
+	}
+
+	if d.HasChange("network_resource_control_enabled") {
+		if err := distributedVirtualSwitchEnableNetworkResourceControl(client, ref, d.Get("network_resource_control_enabled").(bool)); err != nil {
+			return err
+		}
+	}
+
+	return resourceVSphereDistributedVirtualSwitchRead(d, meta)
+}
+
+func resourceVSphereDistributedVirtualSwitchDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	dvs := object.NewDistributedVirtualSwitch(client.Client, distributedVirtualSwitchReference(d.Id()))
+
+	task, err := dvs.Destroy(context.TODO())
+	if err != nil {
+		return fmt.Errorf("error destroying distributed virtual switch %s: %s", d.Id(), err)
+	}
+	if err := task.Wait(context.TODO()); err != nil {
+		return fmt.Errorf("error destroying distributed virtual switch %s: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceVSphereDistributedVirtualSwitchImport imports a distributed virtual
+// switch by its inventory path, such as "/dc1/network/dvs1".
+func resourceVSphereDistributedVirtualSwitchImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*govmomi.Client)
+
+	ref, err := object.NewSearchIndex(client.Client).FindByInventoryPath(context.TODO(), d.Id())
+	if err != nil {
+		return nil, err
+	}
+	if ref == nil || ref.Reference().Type != "VmwareDistributedVirtualSwitch" {
+		return nil, fmt.Errorf("%s is not a distributed virtual switch", d.Id())
+	}
+
+	dcPath, err := datacenterInventoryPath(client, ref.Reference())
+	if err != nil {
+		return nil, err
+	}
+	folder, _, err := splitDatacenterInventoryPath(dcPath, "network", d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("datacenter", strings.TrimPrefix(dcPath, "/"))
+	d.Set("folder", folder)
+	d.SetId(ref.Reference().Value)
+	return []*schema.ResourceData{d}, nil
+}
+
+func distributedVirtualSwitchReference(id string) types.ManagedObjectReference {
+	return types.ManagedObjectReference{
+		Type:  "VmwareDistributedVirtualSwitch",
+		Value: id,
+	}
+}
+
+func distributedVirtualSwitchProperties(client *govmomi.Client, id string) (*mo.VmwareDistributedVirtualSwitch, error) {
+	var mdvs mo.VmwareDistributedVirtualSwitch
+	collector := property.DefaultCollector(client.Client)
+	if err := collector.RetrieveOne(context.TODO(), distributedVirtualSwitchReference(id), []string{"config"}, &mdvs); err != nil {
+		return nil, err
+	}
+	return &mdvs, nil
+}
+
+// distributedVirtualSwitchConfigSpec builds the configuration of a switch,
+// without its host members.
+func distributedVirtualSwitchConfigSpec(d *schema.ResourceData) *types.VMwareDVSConfigSpec {
+	spec := &types.VMwareDVSConfigSpec{
+		DVSConfigSpec: types.DVSConfigSpec{
+			Name:                          d.Get("name").(string),
+			Description:                   d.Get("description").(string),
+			NetworkResourceControlVersion: d.Get("network_resource_control_version").(string),
+		},
+		MaxMtu:         int32(d.Get("max_mtu").(int)),
+		LacpApiVersion: d.Get("lacp_api_version").(string),
+	}
+
+	if v, ok := d.GetOk("uplinks"); ok {
+		spec.UplinkPortPolicy = &types.DVSNameArrayUplinkPortPolicy{
+			UplinkPortName: stringList(v.([]interface{})),
+		}
+	}
+
+	// The LACP policy of the default port configuration only applies to
+	// switches using the single LAG API.
+	if d.HasChange("lacp_enabled") || d.HasChange("lacp_mode") {
+		policy := &types.VMwareUplinkLacpPolicy{
+			Enable: &types.BoolPolicy{
+				Value: types.NewBool(d.Get("lacp_enabled").(bool)),
+			},
+		}
+		if v, ok := d.GetOk("lacp_mode"); ok {
+			policy.Mode = &types.StringPolicy{
+				Value: v.(string),
+			}
+		}
+		spec.DefaultPortConfig = &types.VMwareDVSPortSetting{
+			LacpPolicy: policy,
+		}
+	}
+
+	return spec
+}
+
+func distributedVirtualSwitchHostMemberConfigSpec(op types.ConfigSpecOperation, host map[string]interface{}) types.DistributedVirtualSwitchHostMemberConfigSpec {
+	spec := types.DistributedVirtualSwitchHostMemberConfigSpec{
+		Operation: string(op),
+		Host:      hostSystemReference(host["host_system_id"].(string)),
+	}
+	if op == types.ConfigSpecOperationRemove {
+		return spec
+	}
+
+	backing := &types.DistributedVirtualSwitchHostMemberPnicBacking{}
+	for _, device := range host["devices"].([]interface{}) {
+		backing.PnicSpec = append(backing.PnicSpec, types.DistributedVirtualSwitchHostMemberPnicSpec{
+			PnicDevice: device.(string),
+		})
+	}
+	spec.Backing = backing
+	return spec
+}
+
+func distributedVirtualSwitchEnableNetworkResourceControl(client *govmomi.Client, ref types.ManagedObjectReference, enable bool) error {
+	req := &types.EnableNetworkResourceManagement{
+		This:   ref,
+		Enable: enable,
+	}
+	if _, err := methods.EnableNetworkResourceManagement(context.TODO(), client, req); err != nil {
+		return fmt.Errorf("error changing network I/O control of distributed virtual switch %s: %s", ref.Value, err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/vmware/govmomi"
+)
+
+func TestAccVSphereDistributedVirtualSwitch_basic(t *testing.T) {
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereDistributedVirtualSwitchDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereDistributedVirtualSwitchConfig, datacenter),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereDistributedVirtualSwitchExists("vsphere_distributed_virtual_switch.dvs"),
+					resource.TestCheckResourceAttr("vsphere_distributed_virtual_switch.dvs", "uplinks.#", "2"),
+					resource.TestCheckResourceAttr("vsphere_distributed_virtual_switch.dvs", "uplinks.0", "uplink-a"),
+					resource.TestCheckResourceAttr("vsphere_distributed_virtual_switch.dvs", "max_mtu", "1500"),
+					resource.TestCheckResourceAttr("vsphere_distributed_virtual_switch.dvs", "network_resource_control_enabled", "false"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereDistributedVirtualSwitchConfigUpdated, datacenter),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereDistributedVirtualSwitchExists("vsphere_distributed_virtual_switch.dvs"),
+					resource.TestCheckResourceAttr("vsphere_distributed_virtual_switch.dvs", "uplinks.#", "3"),
+					resource.TestCheckResourceAttr("vsphere_distributed_virtual_switch.dvs", "max_mtu", "9000"),
+					resource.TestCheckResourceAttr("vsphere_distributed_virtual_switch.dvs", "lacp_enabled", "true"),
+					resource.TestCheckResourceAttr("vsphere_distributed_virtual_switch.dvs", "lacp_mode", "active"),
+					resource.TestCheckResourceAttr("vsphere_distributed_virtual_switch.dvs", "network_resource_control_enabled", "true"),
+				),
+			},
+			{
+				ResourceName:      "vsphere_distributed_virtual_switch.dvs",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("/%s/network/terraform-test-dvs", datacenter),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccVSphereDistributedVirtualSwitch_hosts(t *testing.T) {
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+	host := os.Getenv("VSPHERE_HOST_SYSTEM_ID")
+	nic := os.Getenv("VSPHERE_HOST_NIC")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if host == "" || nic == "" {
+				t.Skip("set VSPHERE_HOST_SYSTEM_ID and VSPHERE_HOST_NIC to run distributed virtual switch host member acceptance tests")
+			}
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereDistributedVirtualSwitchDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereDistributedVirtualSwitchConfig, datacenter),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vsphere_distributed_virtual_switch.dvs", "host.#", "0"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereDistributedVirtualSwitchConfigHost, datacenter, host, nic),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vsphere_distributed_virtual_switch.dvs", "host.#", "1"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereDistributedVirtualSwitchConfig, datacenter),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vsphere_distributed_virtual_switch.dvs", "host.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckVSphereDistributedVirtualSwitchDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*govmomi.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "vsphere_distributed_virtual_switch" {
+			continue
+		}
+
+		_, err := distributedVirtualSwitchProperties(client, rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("distributed virtual switch %s still exists", rs.Primary.ID)
+		}
+		if !isManagedObjectNotFoundError(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckVSphereDistributedVirtualSwitchExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*govmomi.Client)
+		mdvs, err := distributedVirtualSwitchProperties(client, rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("error finding distributed virtual switch %s: %s", rs.Primary.ID, err)
+		}
+		if name := mdvs.Config.GetDVSConfigInfo().Name; name != rs.Primary.Attributes["name"] {
+			return fmt.Errorf("expected distributed virtual switch name %s, got %s", rs.Primary.Attributes["name"], name)
+		}
+
+		return nil
+	}
+}
+
+const testAccCheckVSphereDistributedVirtualSwitchConfig = `
+resource "vsphere_distributed_virtual_switch" "dvs" {
+  name       = "terraform-test-dvs"
+  datacenter = "%s"
+  uplinks    = ["uplink-a", "uplink-b"]
+}
+`
+
+const testAccCheckVSphereDistributedVirtualSwitchConfigUpdated = `
+resource "vsphere_distributed_virtual_switch" "dvs" {
+  name       = "terraform-test-dvs"
+  datacenter = "%s"
+  uplinks    = ["uplink-a", "uplink-b", "uplink-c"]
+  max_mtu    = 9000
+
+  lacp_api_version = "singleLag"
+  lacp_enabled     = true
+  lacp_mode        = "active"
+
+  network_resource_control_enabled = true
+}
+`
+
+const testAccCheckVSphereDistributedVirtualSwitchConfigHost = `
+resource "vsphere_distributed_virtual_switch" "dvs" {
+  name       = "terraform-test-dvs"
+  datacenter = "%s"
+  uplinks    = ["uplink-a", "uplink-b"]
+
+  host {
+    host_system_id = "%s"
+    devices        = ["%s"]
+  }
+}
+`
@@ -0,0 +1,195 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+func resourceVSphereEntityPermission() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereEntityPermissionCreate,
+		Read:   resourceVSphereEntityPermissionRead,
+		Update: resourceVSphereEntityPermissionUpdate,
+		Delete: resourceVSphereEntityPermissionDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereEntityPermissionImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"entity_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			// The managed object type of the entity, such as Folder.
+			"entity_type": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"principal": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"is_group": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				ForceNew: true,
+			},
+
+			"role_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"propagate": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		},
+	}
+}
+
+func resourceVSphereEntityPermissionCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	if err := entityPermissionSet(client, d); err != nil {
+		return err
+	}
+
+	d.SetId(entityPermissionID(d.Get("entity_type").(string), d.Get("entity_id").(string), d.Get("principal").(string)))
+	log.Printf("[INFO] Created permission %s", d.Id())
+
+	return resourceVSphereEntityPermissionRead(d, meta)
+}
+
+func resourceVSphereEntityPermissionRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	m := object.NewAuthorizationManager(client.Client)
+
+	entityType, entityID, principal, err := splitEntityPermissionID(d.Id())
+	if err != nil {
+		return err
+	}
+	entity := types.ManagedObjectReference{Type: entityType, Value: entityID}
+
+	permissions, err := m.RetrieveEntityPermissions(context.TODO(), entity, false)
+	if err != nil {
+		if isManagedObjectNotFoundError(err) {
+			log.Printf("[DEBUG] entity of permission %s not found", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("error reading permissions of %s: %s", entityID, err)
+	}
+
+	for _, p := range permissions {
+		if strings.EqualFold(p.Principal, principal) {
+			d.Set("entity_type", entityType)
+			d.Set("entity_id", entityID)
+			// Principals are matched without regard to case, so the
+			// configured spelling is kept when it matches.
+			if !strings.EqualFold(d.Get("principal").(string), p.Principal) {
+				d.Set("principal", p.Principal)
+			}
+			d.Set("is_group", p.Group)
+			d.Set("role_id", strconv.Itoa(int(p.RoleId)))
+			d.Set("propagate", p.Propagate)
+			return nil
+		}
+	}
+
+	log.Printf("[DEBUG] permission %s not found", d.Id())
+	d.SetId("")
+	return nil
+}
+
+func resourceVSphereEntityPermissionUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	// SetEntityPermissions replaces the existing permission of the principal.
+	if err := entityPermissionSet(client, d); err != nil {
+		return err
+	}
+
+	return resourceVSphereEntityPermissionRead(d, meta)
+}
+
+func resourceVSphereEntityPermissionDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	m := object.NewAuthorizationManager(client.Client)
+
+	entity := types.ManagedObjectReference{
+		Type:  d.Get("entity_type").(string),
+		Value: d.Get("entity_id").(string),
+	}
+	principal := d.Get("principal").(string)
+	if err := m.RemoveEntityPermission(context.TODO(), entity, principal, d.Get("is_group").(bool)); err != nil {
+		return fmt.Errorf("error removing permission of %s on %s: %s", principal, entity.Value, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceVSphereEntityPermissionImport imports a permission using the same
+// "<entity_type>:<entity_id>:<principal>" format as the resource ID.
+func resourceVSphereEntityPermissionImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, _, _, err := splitEntityPermissionID(d.Id()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func entityPermissionSet(client *govmomi.Client, d *schema.ResourceData) error {
+	m := object.NewAuthorizationManager(client.Client)
+
+	roleID, err := strconv.Atoi(d.Get("role_id").(string))
+	if err != nil {
+		return fmt.Errorf("invalid role_id %q: %s", d.Get("role_id").(string), err)
+	}
+
+	entity := types.ManagedObjectReference{
+		Type:  d.Get("entity_type").(string),
+		Value: d.Get("entity_id").(string),
+	}
+	permission := types.Permission{
+		Principal: d.Get("principal").(string),
+		Group:     d.Get("is_group").(bool),
+		RoleId:    int32(roleID),
+		Propagate: d.Get("propagate").(bool),
+	}
+	if err := m.SetEntityPermissions(context.TODO(), entity, []types.Permission{permission}); err != nil {
+		return fmt.Errorf("error setting permission of %s on %s: %s", permission.Principal, entity.Value, err)
+	}
+	return nil
+}
+
+func entityPermissionID(entityType, entityID, principal string) string {
+	return fmt.Sprintf("%s:%s:%s", entityType, entityID, principal)
+}
+
+// splitEntityPermissionID splits a permission ID into the type and ID of its
+// entity and its principal. Principals can hold colons, so only the first two
+// separate the parts.
+func splitEntityPermissionID(id string) (string, string, string, error) {
+	parts := strings.SplitN(id, ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("invalid permission ID %q, expected <entity_type>:<entity_id>:<principal>", id)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
@@ -0,0 +1,150 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+func TestAccVSphereEntityPermission_basic(t *testing.T) {
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+	principal := os.Getenv("VSPHERE_PERMISSION_PRINCIPAL")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if principal == "" {
+				t.Skip("set VSPHERE_PERMISSION_PRINCIPAL to run vsphere_entity_permission acceptance tests")
+			}
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereEntityPermissionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereEntityPermissionConfig, datacenter, principal, "true"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereEntityPermissionExists("vsphere_entity_permission.permission"),
+					resource.TestCheckResourceAttr("vsphere_entity_permission.permission", "propagate", "true"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereEntityPermissionConfig, datacenter, principal, "false"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereEntityPermissionExists("vsphere_entity_permission.permission"),
+					resource.TestCheckResourceAttr("vsphere_entity_permission.permission", "propagate", "false"),
+				),
+			},
+			{
+				ResourceName:      "vsphere_entity_permission.permission",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestSplitEntityPermissionID(t *testing.T) {
+	entityType, entityID, principal, err := splitEntityPermissionID(`Folder:group-v22:VSPHERE.LOCAL\app:team`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entityType != "Folder" || entityID != "group-v22" || principal != `VSPHERE.LOCAL\app:team` {
+		t.Fatalf("unexpected parts %q, %q, %q", entityType, entityID, principal)
+	}
+
+	if _, _, _, err := splitEntityPermissionID("Folder:group-v22"); err == nil {
+		t.Fatal("expected an error for an ID without principal")
+	}
+}
+
+func testAccCheckVSphereEntityPermissionDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "vsphere_entity_permission" {
+			continue
+		}
+
+		found, err := testAccEntityPermissionExists(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if found {
+			return fmt.Errorf("permission %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckVSphereEntityPermissionExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		found, err := testAccEntityPermissionExists(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("permission %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccEntityPermissionExists(id string) (bool, error) {
+	client := testAccProvider.Meta().(*govmomi.Client)
+	entityType, entityID, principal, err := splitEntityPermissionID(id)
+	if err != nil {
+		return false, err
+	}
+
+	entity := types.ManagedObjectReference{Type: entityType, Value: entityID}
+	permissions, err := object.NewAuthorizationManager(client.Client).RetrieveEntityPermissions(context.TODO(), entity, false)
+	if err != nil {
+		if isManagedObjectNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, p := range permissions {
+		if strings.EqualFold(p.Principal, principal) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+const testAccCheckVSphereEntityPermissionConfig = `
+resource "vsphere_folder" "folder" {
+  datacenter = "%s"
+  path       = "terraform-test-permission-folder"
+}
+
+resource "vsphere_role" "role" {
+  name       = "terraform-test-permission-role"
+  privileges = ["VirtualMachine.Interact.PowerOn"]
+}
+
+resource "vsphere_entity_permission" "permission" {
+  entity_id   = "${vsphere_folder.folder.moid}"
+  entity_type = "Folder"
+  principal   = "%s"
+  role_id     = "${vsphere_role.role.id}"
+  propagate   = %s
+}
+`
@@ -3,6 +3,7 @@ package vsphere
 import (
 	"fmt"
 	"log"
+	"regexp"
 	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
@@ -13,6 +14,9 @@ import (
 	"golang.org/x/net/context"
 )
 
+// fileIDRegexp matches the ID of a vsphere_file resource.
+var fileIDRegexp = regexp.MustCompile(`^\[([^\]]+)\] ([^/]*)/(.+)$`)
+
 type file struct {
 	sourceDatacenter  string
 	datacenter        string
@@ -30,6 +34,9 @@ func resourceVSphereFile() *schema.Resource {
 		Read:   resourceVSphereFileRead,
 		Update: resourceVSphereFileUpdate,
 		Delete: resourceVSphereFileDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereFileImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"datacenter": {
@@ -58,6 +65,11 @@ func resourceVSphereFile() *schema.Resource {
 				Type:     schema.TypeString,
 				Required: true,
 				ForceNew: true,
+				// The source of an imported file is unknown, so it is left
+				// empty in state and must not force a new file.
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return old == "" && d.Id() != ""
+				},
 			},
 
 			"destination_file": {
@@ -217,8 +229,6 @@ func resourceVSphereFileRead(d *schema.ResourceData, meta interface{}) error {
 
 	if v, ok := d.GetOk("source_file"); ok {
 		f.sourceFile = v.(string)
-	} else {
-		return fmt.Errorf("source_file argument is required")
 	}
 
 	if v, ok := d.GetOk("destination_file"); ok {
@@ -341,12 +351,6 @@ func resourceVSphereFileDelete(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("datastore argument is required")
 	}
 
-	if v, ok := d.GetOk("source_file"); ok {
-		f.sourceFile = v.(string)
-	} else {
-		return fmt.Errorf("source_file argument is required")
-	}
-
 	if v, ok := d.GetOk("destination_file"); ok {
 		f.destinationFile = v.(string)
 	} else {
@@ -364,6 +368,28 @@ func resourceVSphereFileDelete(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+// resourceVSphereFileImport imports a file using the same
+// "[<datastore>] <datacenter>/<destination_file>" format as the resource ID.
+// The datacenter can be left empty to use the default datacenter.
+func resourceVSphereFileImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	m := fileIDRegexp.FindStringSubmatch(d.Id())
+	if m == nil {
+		return nil, fmt.Errorf("file ID must be in the format [<datastore>] <datacenter>/<path>, got %s", d.Id())
+	}
+
+	d.Set("datastore", m[1])
+	d.Set("datacenter", m[2])
+	d.Set("destination_file", m[3])
+
+	if err := resourceVSphereFileRead(d, meta); err != nil {
+		return nil, err
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("file %s not found", m[3])
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
 func deleteFile(client *govmomi.Client, f *file) error {
 
 	dc, err := getDatacenter(client, f.datacenter)
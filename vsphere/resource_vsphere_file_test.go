@@ -55,6 +55,47 @@ func TestAccVSphereFile_basic(t *testing.T) {
 	os.Remove(testVmdkFile)
 }
 
+func TestAccVSphereFile_importBasic(t *testing.T) {
+	testVmdkFileData := []byte("# Disk DescriptorFile\n")
+	testVmdkFile := "/tmp/tf_test.vmdk"
+	err := ioutil.WriteFile(testVmdkFile, testVmdkFileData, 0644)
+	if err != nil {
+		t.Errorf("error %s", err)
+		return
+	}
+
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+	datastore := os.Getenv("VSPHERE_DATASTORE")
+	testMethod := "import"
+	resourceName := "vsphere_file." + testMethod
+	destinationFile := "tf_file_test.vmdk"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereFileDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(
+					testAccCheckVSphereFileConfig,
+					testMethod,
+					datacenter,
+					datastore,
+					testVmdkFile,
+					destinationFile,
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"source_file", "create_directories"},
+			},
+		},
+	})
+	os.Remove(testVmdkFile)
+}
+
 // Basic file copy within vSphere
 func TestAccVSphereFile_basicUploadAndCopy(t *testing.T) {
 	testVmdkFileData := []byte("# Disk DescriptorFile\n")
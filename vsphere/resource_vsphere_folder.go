@@ -10,6 +10,9 @@ import (
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
 	"golang.org/x/net/context"
 )
 
@@ -23,7 +26,11 @@ func resourceVSphereFolder() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceVSphereFolderCreate,
 		Read:   resourceVSphereFolderRead,
+		Update: resourceVSphereFolderUpdate,
 		Delete: resourceVSphereFolderDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereFolderImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"datacenter": &schema.Schema{
@@ -42,6 +49,13 @@ func resourceVSphereFolder() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+
+			"custom_attributes": customAttributesSchema(),
+
+			"moid": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 	}
 }
@@ -67,6 +81,16 @@ func resourceVSphereFolderCreate(d *schema.ResourceData, meta interface{}) error
 	d.SetId(fmt.Sprintf("%v/%v", f.datacenter, f.path))
 	log.Printf("[INFO] Created folder: %s", f.path)
 
+	ref, err := folderReference(client, d)
+	if err != nil {
+		return err
+	}
+	if ref != nil {
+		if err := applyCustomAttributes(client, *ref, d); err != nil {
+			return err
+		}
+	}
+
 	return resourceVSphereFolderRead(d, meta)
 }
 
@@ -138,9 +162,74 @@ func resourceVSphereFolderRead(d *schema.ResourceData, meta interface{}) error {
 
 	if folder == nil {
 		d.SetId("")
+		return nil
 	}
 
-	return nil
+	d.Set("moid", folder.Reference().Value)
+	return readCustomAttributes(client, folder.Reference(), d)
+}
+
+func resourceVSphereFolderUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	ref, err := folderReference(client, d)
+	if err != nil {
+		return err
+	}
+	if ref == nil {
+		return fmt.Errorf("folder %s not found", d.Id())
+	}
+	if err := applyCustomAttributes(client, *ref, d); err != nil {
+		return err
+	}
+
+	return resourceVSphereFolderRead(d, meta)
+}
+
+// folderReference returns the managed object reference of the folder of the
+// resource, or nil if the folder does not exist.
+func folderReference(client *govmomi.Client, d *schema.ResourceData) (*types.ManagedObjectReference, error) {
+	folder, err := object.NewSearchIndex(client.Client).FindByInventoryPath(
+		context.TODO(), fmt.Sprintf("%v/vm/%v", d.Get("datacenter").(string),
+			strings.TrimRight(d.Get("path").(string), "/")))
+	if err != nil {
+		return nil, err
+	}
+	if folder == nil {
+		return nil, nil
+	}
+	ref := folder.Reference()
+	return &ref, nil
+}
+
+// resourceVSphereFolderImport imports a folder using the same
+// "<datacenter>/<path>" format as the resource ID. Only the last folder of the
+// path is treated as managed, so destroying an imported folder never removes
+// its parents.
+func resourceVSphereFolderImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 || strings.Trim(parts[1], "/") == "" {
+		return nil, fmt.Errorf("folder ID must be in the format <datacenter>/<path>, got %s", d.Id())
+	}
+
+	folderPath := strings.Trim(parts[1], "/")
+	existingPath := path.Dir(folderPath)
+	if existingPath == "." {
+		existingPath = ""
+	}
+
+	d.Set("datacenter", parts[0])
+	d.Set("path", folderPath)
+	d.Set("existing_path", existingPath)
+	d.SetId(fmt.Sprintf("%v/%v", parts[0], folderPath))
+
+	if err := resourceVSphereFolderRead(d, meta); err != nil {
+		return nil, err
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("folder %s not found in datacenter %q", folderPath, parts[0])
+	}
+	return []*schema.ResourceData{d}, nil
 }
 
 func resourceVSphereFolderDelete(d *schema.ResourceData, meta interface{}) error {
@@ -235,3 +324,65 @@ func getDatacenter(c *govmomi.Client, dc string) (*object.Datacenter, error) {
 		return d, err
 	}
 }
+
+// datacenterForEntity returns the datacenter that the given managed entity
+// belongs to. Virtual machines in a vApp are followed through the vApp.
+func datacenterForEntity(client *govmomi.Client, ref types.ManagedObjectReference) (types.ManagedObjectReference, error) {
+	collector := property.DefaultCollector(client.Client)
+	for ref.Type != "Datacenter" {
+		var parent *types.ManagedObjectReference
+		if ref.Type == "VirtualMachine" {
+			var mvm mo.VirtualMachine
+			if err := collector.RetrieveOne(context.TODO(), ref, []string{"parent", "parentVApp"}, &mvm); err != nil {
+				return types.ManagedObjectReference{}, err
+			}
+			parent = mvm.Parent
+			if parent == nil {
+				parent = mvm.ParentVApp
+			}
+		} else {
+			var me mo.ManagedEntity
+			if err := collector.RetrieveOne(context.TODO(), ref, []string{"parent"}, &me); err != nil {
+				return types.ManagedObjectReference{}, err
+			}
+			parent = me.Parent
+		}
+		if parent == nil {
+			return types.ManagedObjectReference{}, fmt.Errorf("%s is not in a datacenter", ref.Value)
+		}
+		ref = *parent
+	}
+	return ref, nil
+}
+
+// datacenterInventoryPath returns the inventory path of the datacenter that
+// the given managed entity belongs to, such as "/dc1".
+func datacenterInventoryPath(client *govmomi.Client, ref types.ManagedObjectReference) (string, error) {
+	dcRef, err := datacenterForEntity(client, ref)
+	if err != nil {
+		return "", fmt.Errorf("error finding datacenter of %s: %s", ref.Value, err)
+	}
+	e, err := find.NewFinder(client.Client, true).Element(context.TODO(), dcRef)
+	if err != nil {
+		return "", fmt.Errorf("error finding inventory path of datacenter %s: %s", dcRef.Value, err)
+	}
+	return e.Path, nil
+}
+
+// splitDatacenterInventoryPath splits the full inventory path of an object,
+// such as "/dc1/host/prod/cluster1", into the folder relative to the
+// datacenter's root folder of the given kind ("vm", "host", "network" or
+// "datastore") and the name of the object, given the inventory path of its
+// datacenter, such as "/dc1".
+func splitDatacenterInventoryPath(dcPath, kind, p string) (string, string, error) {
+	p = "/" + strings.TrimPrefix(p, "/")
+	rel := strings.TrimPrefix(p, dcPath+"/"+kind+"/")
+	if rel == p || rel == "" {
+		return "", "", fmt.Errorf("%s is not in the %s folder of datacenter %s", p, kind, dcPath)
+	}
+	folder := path.Dir(rel)
+	if folder == "." {
+		folder = ""
+	}
+	return folder, path.Base(rel), nil
+}
@@ -13,6 +13,39 @@ import (
 	"golang.org/x/net/context"
 )
 
+func TestSplitDatacenterInventoryPath(t *testing.T) {
+	cases := []struct {
+		dcPath string
+		kind   string
+		path   string
+		folder string
+		name   string
+		err    bool
+	}{
+		{"/dc1", "vm", "/dc1/vm/web01", "", "web01", false},
+		{"/dc1", "vm", "/dc1/vm/web/frontend/web01", "web/frontend", "web01", false},
+		{"/dcfolder/dc1", "vm", "/dcfolder/dc1/vm/web/web01", "web", "web01", false},
+		{"/vm/dc1", "vm", "/vm/dc1/vm/web/web01", "web", "web01", false},
+		{"/dc1", "vm", "/dc1/vm/vm/web01", "vm", "web01", false},
+		{"/east/host/dc1", "host", "/east/host/dc1/host/c1", "", "c1", false},
+		{"/dc1", "host", "dc1/host/prod/c1", "prod", "c1", false},
+		{"/dc1", "network", "/dc1/network/dvs1", "", "dvs1", false},
+		{"/dc1", "datastore", "/dc1/datastore/pods/pod1", "pods", "pod1", false},
+		{"/dc1", "vm", "/dc1/host/cluster1", "", "", true},
+		{"/dc2", "vm", "/dc1/vm/web01", "", "", true},
+	}
+
+	for _, tc := range cases {
+		folder, name, err := splitDatacenterInventoryPath(tc.dcPath, tc.kind, tc.path)
+		if (err != nil) != tc.err {
+			t.Fatalf("%s: unexpected error state: %v", tc.path, err)
+		}
+		if folder != tc.folder || name != tc.name {
+			t.Fatalf("%s: expected (%q, %q), got (%q, %q)", tc.path, tc.folder, tc.name, folder, name)
+		}
+	}
+}
+
 // Basic top-level folder creation
 func TestAccVSphereFolder_basic(t *testing.T) {
 	var f folder
@@ -45,6 +78,34 @@ func TestAccVSphereFolder_basic(t *testing.T) {
 	})
 }
 
+func TestAccVSphereFolder_importBasic(t *testing.T) {
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+	testMethod := "import"
+	resourceName := "vsphere_folder." + testMethod
+	path := "tf_test_import"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereFolderDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(
+					testAccCheckVSphereFolderConfig,
+					testMethod,
+					path,
+					datacenter,
+				),
+			},
+			resource.TestStep{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func TestAccVSphereFolder_nested(t *testing.T) {
 
 	var f folder
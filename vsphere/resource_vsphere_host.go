@@ -0,0 +1,317 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+func resourceVSphereHost() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereHostCreate,
+		Read:   resourceVSphereHostRead,
+		Update: resourceVSphereHostUpdate,
+		Delete: resourceVSphereHostDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereHostImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"hostname": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"username": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"password": &schema.Schema{
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+			},
+
+			"thumbprint": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"datacenter": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"cluster_id"},
+			},
+
+			"cluster_id": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"datacenter"},
+			},
+
+			"force": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"connected": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+
+			"maintenance": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+func resourceVSphereHostCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	spec := hostConnectSpec(d)
+	connected := d.Get("connected").(bool)
+
+	var hostRef types.ManagedObjectReference
+	if v, ok := d.GetOk("cluster_id"); ok {
+		cluster := object.NewClusterComputeResource(client.Client, computeClusterReference(v.(string)))
+		task, err := cluster.AddHost(context.TODO(), spec, connected, nil, nil)
+		if err != nil {
+			return fmt.Errorf("error adding host %s: %s", spec.HostName, err)
+		}
+		info, err := task.WaitForResult(context.TODO(), nil)
+		if err != nil {
+			return fmt.Errorf("error adding host %s: %s", spec.HostName, err)
+		}
+		hostRef = info.Result.(types.ManagedObjectReference)
+	} else {
+		dc, err := getDatacenter(client, d.Get("datacenter").(string))
+		if err != nil {
+			return fmt.Errorf("error finding datacenter: %s", err)
+		}
+		dcFolders, err := dc.Folders(context.TODO())
+		if err != nil {
+			return err
+		}
+		task, err := dcFolders.HostFolder.AddStandaloneHost(context.TODO(), spec, connected, nil, nil)
+		if err != nil {
+			return fmt.Errorf("error adding host %s: %s", spec.HostName, err)
+		}
+		info, err := task.WaitForResult(context.TODO(), nil)
+		if err != nil {
+			return fmt.Errorf("error adding host %s: %s", spec.HostName, err)
+		}
+
+		// A standalone host gets its own compute resource, which holds the host.
+		var mcr mo.ComputeResource
+		collector := property.DefaultCollector(client.Client)
+		if err := collector.RetrieveOne(context.TODO(), info.Result.(types.ManagedObjectReference), []string{"host"}, &mcr); err != nil {
+			return err
+		}
+		if len(mcr.Host) != 1 {
+			return fmt.Errorf("expected a single host in compute resource %s, got %d", mcr.Self.Value, len(mcr.Host))
+		}
+		hostRef = mcr.Host[0]
+	}
+	log.Printf("[INFO] Added host %s: %s", spec.HostName, hostRef.Value)
+
+	d.SetId(hostRef.Value)
+
+	if d.Get("maintenance").(bool) {
+		if err := hostSetMaintenanceMode(object.NewHostSystem(client.Client, hostRef), true); err != nil {
+			return err
+		}
+	}
+
+	return resourceVSphereHostRead(d, meta)
+}
+
+func resourceVSphereHostRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	var mhs mo.HostSystem
+	collector := property.DefaultCollector(client.Client)
+	if err := collector.RetrieveOne(context.TODO(), hostSystemReference(d.Id()), []string{"parent", "runtime"}, &mhs); err != nil {
+		if isManagedObjectNotFoundError(err) {
+			log.Printf("[DEBUG] host %s not found: %s", d.Id(), err)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	if mhs.Parent != nil && mhs.Parent.Type == "ClusterComputeResource" {
+		d.Set("cluster_id", mhs.Parent.Value)
+	} else {
+		d.Set("cluster_id", "")
+	}
+	d.Set("connected", mhs.Runtime.ConnectionState == types.HostSystemConnectionStateConnected)
+	d.Set("maintenance", mhs.Runtime.InMaintenanceMode)
+
+	return nil
+}
+
+func resourceVSphereHostUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	host := object.NewHostSystem(client.Client, hostSystemReference(d.Id()))
+
+	connected := d.Get("connected").(bool)
+	credentialsChanged := d.HasChange("username") || d.HasChange("password") || d.HasChange("thumbprint")
+
+	// Maintenance mode can only be changed on connected hosts, so connect first
+	// and disconnect last.
+	if connected && (d.HasChange("connected") || credentialsChanged) {
+		spec := hostConnectSpec(d)
+		task, err := host.Reconnect(context.TODO(), &spec, nil)
+		if err != nil {
+			return fmt.Errorf("error connecting host %s: %s", d.Id(), err)
+		}
+		if err := task.Wait(context.TODO()); err != nil {
+			return fmt.Errorf("error connecting host %s: %s", d.Id(), err)
+		}
+	}
+
+	if d.HasChange("maintenance") {
+		if err := hostSetMaintenanceMode(host, d.Get("maintenance").(bool)); err != nil {
+			return err
+		}
+	}
+
+	if !connected && d.HasChange("connected") {
+		task, err := host.Disconnect(context.TODO())
+		if err != nil {
+			return fmt.Errorf("error disconnecting host %s: %s", d.Id(), err)
+		}
+		if err := task.Wait(context.TODO()); err != nil {
+			return fmt.Errorf("error disconnecting host %s: %s", d.Id(), err)
+		}
+	}
+
+	return resourceVSphereHostRead(d, meta)
+}
+
+func resourceVSphereHostDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	host := object.NewHostSystem(client.Client, hostSystemReference(d.Id()))
+
+	var mhs mo.HostSystem
+	collector := property.DefaultCollector(client.Client)
+	if err := collector.RetrieveOne(context.TODO(), host.Reference(), []string{"parent", "runtime"}, &mhs); err != nil {
+		return err
+	}
+
+	if mhs.Runtime.ConnectionState == types.HostSystemConnectionStateConnected && !mhs.Runtime.InMaintenanceMode {
+		if err := hostSetMaintenanceMode(host, true); err != nil {
+			return err
+		}
+	}
+
+	// A standalone host is removed along with the compute resource holding it.
+	var task *object.Task
+	var err error
+	if mhs.Parent != nil && mhs.Parent.Type == "ComputeResource" {
+		task, err = object.NewComputeResource(client.Client, *mhs.Parent).Destroy(context.TODO())
+	} else {
+		task, err = host.Destroy(context.TODO())
+	}
+	if err != nil {
+		return fmt.Errorf("error removing host %s: %s", d.Id(), err)
+	}
+	if err := task.Wait(context.TODO()); err != nil {
+		return fmt.Errorf("error removing host %s: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceVSphereHostImport imports a host by its inventory path, such as
+// "/dc1/host/cluster1/esxi01.example.com". The credentials of the host cannot
+// be read back and need to be set in the configuration.
+func resourceVSphereHostImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*govmomi.Client)
+
+	ref, err := object.NewSearchIndex(client.Client).FindByInventoryPath(context.TODO(), d.Id())
+	if err != nil {
+		return nil, err
+	}
+	host, ok := ref.(*object.HostSystem)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a host", d.Id())
+	}
+
+	dcPath, err := datacenterInventoryPath(client, host.Reference())
+	if err != nil {
+		return nil, err
+	}
+	_, name, err := splitDatacenterInventoryPath(dcPath, "host", d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	var mhs mo.HostSystem
+	collector := property.DefaultCollector(client.Client)
+	if err := collector.RetrieveOne(context.TODO(), host.Reference(), []string{"parent"}, &mhs); err != nil {
+		return nil, err
+	}
+	if mhs.Parent != nil && mhs.Parent.Type == "ComputeResource" {
+		d.Set("datacenter", strings.TrimPrefix(dcPath, "/"))
+	}
+	d.Set("hostname", name)
+	d.Set("force", false)
+
+	d.SetId(host.Reference().Value)
+	return []*schema.ResourceData{d}, nil
+}
+
+func hostSystemReference(id string) types.ManagedObjectReference {
+	return types.ManagedObjectReference{
+		Type:  "HostSystem",
+		Value: id,
+	}
+}
+
+func hostConnectSpec(d *schema.ResourceData) types.HostConnectSpec {
+	return types.HostConnectSpec{
+		HostName:      d.Get("hostname").(string),
+		UserName:      d.Get("username").(string),
+		Password:      d.Get("password").(string),
+		SslThumbprint: d.Get("thumbprint").(string),
+		Force:         d.Get("force").(bool),
+	}
+}
+
+// hostSetMaintenanceMode puts a host into maintenance mode, or takes it out of
+// it, and waits for the operation to complete. Powered on virtual machines
+// are evacuated when the host is in a cluster with DRS fully automated.
+func hostSetMaintenanceMode(host *object.HostSystem, maintenance bool) error {
+	var task *object.Task
+	var err error
+	if maintenance {
+		task, err = host.EnterMaintenanceMode(context.TODO(), 0, true, nil)
+	} else {
+		task, err = host.ExitMaintenanceMode(context.TODO(), 0)
+	}
+	if err != nil {
+		return fmt.Errorf("error changing maintenance mode of host %s: %s", host.Reference().Value, err)
+	}
+	if err := task.Wait(context.TODO()); err != nil {
+		return fmt.Errorf("error changing maintenance mode of host %s: %s", host.Reference().Value, err)
+	}
+	return nil
+}
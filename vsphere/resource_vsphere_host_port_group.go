@@ -0,0 +1,266 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+func resourceVSphereHostPortGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereHostPortGroupCreate,
+		Read:   resourceVSphereHostPortGroupRead,
+		Update: resourceVSphereHostPortGroupUpdate,
+		Delete: resourceVSphereHostPortGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereHostPortGroupImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"host_system_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"virtual_switch_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"vlan_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  0,
+			},
+
+			// The following arguments override the policy of the virtual switch
+			// when set.
+			"active_nics": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"standby_nics": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"teaming_policy": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateStringInSlice(hostNicTeamingPolicies),
+			},
+
+			"allow_promiscuous": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
+			"allow_forged_transmits": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
+			"allow_mac_changes": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
+			"key": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceVSphereHostPortGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	hostID := d.Get("host_system_id").(string)
+	name := d.Get("name").(string)
+
+	ns, err := hostNetworkSystemFromHostSystemID(client, hostID)
+	if err != nil {
+		return err
+	}
+
+	if err := ns.AddPortGroup(context.TODO(), hostPortGroupSpec(d)); err != nil {
+		return fmt.Errorf("error adding port group %s to host %s: %s", name, hostID, err)
+	}
+	log.Printf("[INFO] Added port group %s to host %s", name, hostID)
+
+	d.SetId(hostNetworkID(hostID, name))
+
+	return resourceVSphereHostPortGroupRead(d, meta)
+}
+
+func resourceVSphereHostPortGroupRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	hostID, name, err := splitHostNetworkID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	ns, err := hostNetworkSystemFromHostSystemID(client, hostID)
+	if err != nil {
+		return err
+	}
+	info, err := hostNetworkInfo(ns)
+	if err != nil {
+		return err
+	}
+
+	var pg *types.HostPortGroup
+	for i := range info.Portgroup {
+		if info.Portgroup[i].Spec.Name == name {
+			pg = &info.Portgroup[i]
+			break
+		}
+	}
+	if pg == nil {
+		log.Printf("[DEBUG] port group %s not found on host %s", name, hostID)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", name)
+	d.Set("host_system_id", hostID)
+	d.Set("key", pg.Key)
+	d.Set("virtual_switch_name", pg.Spec.VswitchName)
+	d.Set("vlan_id", pg.Spec.VlanId)
+
+	// Only the overrides of the port group are read back; policies inherited
+	// from the virtual switch are left unset.
+	policy := pg.Spec.Policy
+	var active, standby []string
+	var teamingPolicy string
+	if teaming := policy.NicTeaming; teaming != nil {
+		teamingPolicy = teaming.Policy
+		if teaming.NicOrder != nil {
+			active = teaming.NicOrder.ActiveNic
+			standby = teaming.NicOrder.StandbyNic
+		}
+	}
+	d.Set("teaming_policy", teamingPolicy)
+	d.Set("active_nics", active)
+	d.Set("standby_nics", standby)
+
+	security := policy.Security
+	if security == nil {
+		security = &types.HostNetworkSecurityPolicy{}
+	}
+	setBoolOverride(d, "allow_promiscuous", security.AllowPromiscuous)
+	setBoolOverride(d, "allow_forged_transmits", security.ForgedTransmits)
+	setBoolOverride(d, "allow_mac_changes", security.MacChanges)
+
+	return nil
+}
+
+func resourceVSphereHostPortGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	hostID, name, err := splitHostNetworkID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	ns, err := hostNetworkSystemFromHostSystemID(client, hostID)
+	if err != nil {
+		return err
+	}
+
+	if err := ns.UpdatePortGroup(context.TODO(), name, hostPortGroupSpec(d)); err != nil {
+		return fmt.Errorf("error updating port group %s on host %s: %s", name, hostID, err)
+	}
+
+	return resourceVSphereHostPortGroupRead(d, meta)
+}
+
+func resourceVSphereHostPortGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	hostID, name, err := splitHostNetworkID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	ns, err := hostNetworkSystemFromHostSystemID(client, hostID)
+	if err != nil {
+		return err
+	}
+
+	if err := ns.RemovePortGroup(context.TODO(), name); err != nil {
+		return fmt.Errorf("error removing port group %s from host %s: %s", name, hostID, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceVSphereHostPortGroupImport imports a port group using the same
+// "<host_system_id>:<name>" format as the resource ID.
+func resourceVSphereHostPortGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, _, err := splitHostNetworkID(d.Id()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func hostPortGroupSpec(d *schema.ResourceData) types.HostPortGroupSpec {
+	spec := types.HostPortGroupSpec{
+		Name:        d.Get("name").(string),
+		VswitchName: d.Get("virtual_switch_name").(string),
+		VlanId:      int32(d.Get("vlan_id").(int)),
+		Policy: types.HostNetworkPolicy{
+			Security: &types.HostNetworkSecurityPolicy{
+				AllowPromiscuous: getBoolOverride(d, "allow_promiscuous"),
+				ForgedTransmits:  getBoolOverride(d, "allow_forged_transmits"),
+				MacChanges:       getBoolOverride(d, "allow_mac_changes"),
+			},
+			NicTeaming: &types.HostNicTeamingPolicy{
+				Policy: d.Get("teaming_policy").(string),
+			},
+		},
+	}
+
+	active := stringList(d.Get("active_nics").([]interface{}))
+	standby := stringList(d.Get("standby_nics").([]interface{}))
+	if len(active) > 0 || len(standby) > 0 {
+		spec.Policy.NicTeaming.NicOrder = &types.HostNicOrderPolicy{
+			ActiveNic:  active,
+			StandbyNic: standby,
+		}
+	}
+
+	return spec
+}
+
+// getBoolOverride returns the value of an optional boolean argument, or nil
+// if it is not set so that the value is inherited.
+func getBoolOverride(d *schema.ResourceData, key string) *bool {
+	if v, ok := d.GetOkExists(key); ok {
+		return types.NewBool(v.(bool))
+	}
+	return nil
+}
+
+// setBoolOverride sets an optional boolean argument, clearing it if v is nil.
+func setBoolOverride(d *schema.ResourceData, key string, v *bool) {
+	if v == nil {
+		d.Set(key, nil)
+		return
+	}
+	d.Set(key, *v)
+}
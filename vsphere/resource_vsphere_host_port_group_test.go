@@ -0,0 +1,150 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestAccVSphereHostPortGroup_basic(t *testing.T) {
+	host := os.Getenv("VSPHERE_HOST_SYSTEM_ID")
+	nic := os.Getenv("VSPHERE_HOST_NIC")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccVSphereHostNetworkPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereHostPortGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereHostPortGroupConfig, host, nic, nic),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereHostPortGroupExists("vsphere_host_port_group.pg"),
+					resource.TestCheckResourceAttr("vsphere_host_port_group.pg", "vlan_id", "100"),
+					resource.TestCheckResourceAttr("vsphere_host_port_group.pg", "allow_promiscuous", ""),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereHostPortGroupConfigOverrides, host, nic, nic),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereHostPortGroupExists("vsphere_host_port_group.pg"),
+					resource.TestCheckResourceAttr("vsphere_host_port_group.pg", "vlan_id", "200"),
+					resource.TestCheckResourceAttr("vsphere_host_port_group.pg", "allow_promiscuous", "true"),
+					resource.TestCheckResourceAttr("vsphere_host_port_group.pg", "allow_forged_transmits", "false"),
+				),
+			},
+			{
+				ResourceName:      "vsphere_host_port_group.pg",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckVSphereHostPortGroupDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "vsphere_host_port_group" {
+			continue
+		}
+
+		pg, err := testAccGetHostPortGroup(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if pg != nil {
+			return fmt.Errorf("port group %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckVSphereHostPortGroupExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		pg, err := testAccGetHostPortGroup(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if pg == nil {
+			return fmt.Errorf("port group %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccGetHostPortGroup(id string) (*types.HostPortGroup, error) {
+	client := testAccProvider.Meta().(*govmomi.Client)
+	hostID, name, err := splitHostNetworkID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ns, err := hostNetworkSystemFromHostSystemID(client, hostID)
+	if err != nil {
+		return nil, err
+	}
+	info, err := hostNetworkInfo(ns)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pg := range info.Portgroup {
+		if pg.Spec.Name == name {
+			return &pg, nil
+		}
+	}
+	return nil, nil
+}
+
+const testAccCheckVSphereHostPortGroupConfig = `
+resource "vsphere_host_virtual_switch" "switch" {
+  name             = "vSwitchTerraformTest"
+  host_system_id   = "%s"
+  network_adapters = ["%s"]
+  active_nics      = ["%s"]
+}
+
+resource "vsphere_host_port_group" "pg" {
+  name                = "PGTerraformTest"
+  host_system_id      = "${vsphere_host_virtual_switch.switch.host_system_id}"
+  virtual_switch_name = "${vsphere_host_virtual_switch.switch.name}"
+  vlan_id             = 100
+}
+`
+
+const testAccCheckVSphereHostPortGroupConfigOverrides = `
+resource "vsphere_host_virtual_switch" "switch" {
+  name             = "vSwitchTerraformTest"
+  host_system_id   = "%s"
+  network_adapters = ["%s"]
+  active_nics      = ["%s"]
+}
+
+resource "vsphere_host_port_group" "pg" {
+  name                = "PGTerraformTest"
+  host_system_id      = "${vsphere_host_virtual_switch.switch.host_system_id}"
+  virtual_switch_name = "${vsphere_host_virtual_switch.switch.name}"
+  vlan_id             = 200
+
+  allow_promiscuous      = true
+  allow_forged_transmits = false
+}
+`
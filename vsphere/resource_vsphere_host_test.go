@@ -0,0 +1,170 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/mo"
+	"golang.org/x/net/context"
+)
+
+func TestAccVSphereHost_standalone(t *testing.T) {
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccVSphereHostPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereHostDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckVSphereHostConfigStandalone(datacenter, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereHostExists("vsphere_host.esxi"),
+					resource.TestCheckResourceAttr("vsphere_host.esxi", "connected", "true"),
+					resource.TestCheckResourceAttr("vsphere_host.esxi", "maintenance", "false"),
+					resource.TestCheckResourceAttr("vsphere_host.esxi", "cluster_id", ""),
+				),
+			},
+			{
+				Config: testAccCheckVSphereHostConfigStandalone(datacenter, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereHostExists("vsphere_host.esxi"),
+					resource.TestCheckResourceAttr("vsphere_host.esxi", "maintenance", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccVSphereHost_cluster(t *testing.T) {
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccVSphereHostPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereHostDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckVSphereHostConfigCluster(datacenter),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereHostExists("vsphere_host.esxi"),
+					resource.TestCheckResourceAttrPair("vsphere_host.esxi", "cluster_id", "vsphere_compute_cluster.cluster", "id"),
+				),
+			},
+			{
+				ResourceName:            "vsphere_host.esxi",
+				ImportState:             true,
+				ImportStateId:           fmt.Sprintf("/%s/host/terraform-test-cluster/%s", datacenter, os.Getenv("VSPHERE_ESXI_HOST")),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"username", "password", "thumbprint"},
+			},
+		},
+	})
+}
+
+func testAccVSphereHostPreCheck(t *testing.T) {
+	for _, k := range []string{"VSPHERE_ESXI_HOST", "VSPHERE_ESXI_USER", "VSPHERE_ESXI_PASSWORD", "VSPHERE_ESXI_THUMBPRINT"} {
+		if v := os.Getenv(k); v == "" {
+			t.Fatalf("env variable %s must be set for acceptance tests", k)
+		}
+	}
+}
+
+func testAccCheckVSphereHostDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*govmomi.Client)
+	collector := property.DefaultCollector(client.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "vsphere_host" {
+			continue
+		}
+
+		var mhs mo.HostSystem
+		err := collector.RetrieveOne(context.TODO(), hostSystemReference(rs.Primary.ID), []string{"name"}, &mhs)
+		if err == nil {
+			return fmt.Errorf("host %s still exists", rs.Primary.ID)
+		}
+		if !isManagedObjectNotFoundError(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckVSphereHostExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*govmomi.Client)
+		collector := property.DefaultCollector(client.Client)
+
+		var mhs mo.HostSystem
+		if err := collector.RetrieveOne(context.TODO(), hostSystemReference(rs.Primary.ID), []string{"name"}, &mhs); err != nil {
+			return fmt.Errorf("error finding host %s: %s", rs.Primary.ID, err)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckVSphereHostConfigStandalone(datacenter string, maintenance bool) string {
+	return fmt.Sprintf(`
+resource "vsphere_host" "esxi" {
+  hostname    = "%s"
+  username    = "%s"
+  password    = "%s"
+  thumbprint  = "%s"
+  datacenter  = "%s"
+  maintenance = %t
+}
+`,
+		os.Getenv("VSPHERE_ESXI_HOST"),
+		os.Getenv("VSPHERE_ESXI_USER"),
+		os.Getenv("VSPHERE_ESXI_PASSWORD"),
+		os.Getenv("VSPHERE_ESXI_THUMBPRINT"),
+		datacenter,
+		maintenance,
+	)
+}
+
+func testAccCheckVSphereHostConfigCluster(datacenter string) string {
+	return fmt.Sprintf(`
+resource "vsphere_compute_cluster" "cluster" {
+  name       = "terraform-test-cluster"
+  datacenter = "%s"
+}
+
+resource "vsphere_host" "esxi" {
+  hostname   = "%s"
+  username   = "%s"
+  password   = "%s"
+  thumbprint = "%s"
+  cluster_id = "${vsphere_compute_cluster.cluster.id}"
+}
+`,
+		datacenter,
+		os.Getenv("VSPHERE_ESXI_HOST"),
+		os.Getenv("VSPHERE_ESXI_USER"),
+		os.Getenv("VSPHERE_ESXI_PASSWORD"),
+		os.Getenv("VSPHERE_ESXI_THUMBPRINT"),
+	)
+}
@@ -0,0 +1,320 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+var hostNicTeamingPolicies = []string{
+	"loadbalance_ip",
+	"loadbalance_srcmac",
+	"loadbalance_srcid",
+	"failover_explicit",
+}
+
+func resourceVSphereHostVirtualSwitch() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereHostVirtualSwitchCreate,
+		Read:   resourceVSphereHostVirtualSwitchRead,
+		Update: resourceVSphereHostVirtualSwitchUpdate,
+		Delete: resourceVSphereHostVirtualSwitchDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereHostVirtualSwitchImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"host_system_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"number_of_ports": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  128,
+			},
+
+			"mtu": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  1500,
+			},
+
+			"network_adapters": &schema.Schema{
+				Type:     schema.TypeList,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"active_nics": &schema.Schema{
+				Type:     schema.TypeList,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"standby_nics": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"teaming_policy": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "loadbalance_srcid",
+				ValidateFunc: validateStringInSlice(hostNicTeamingPolicies),
+			},
+
+			"notify_switches": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+
+			"failback": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+
+			"allow_promiscuous": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"allow_forged_transmits": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+
+			"allow_mac_changes": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		},
+	}
+}
+
+func resourceVSphereHostVirtualSwitchCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	hostID := d.Get("host_system_id").(string)
+	name := d.Get("name").(string)
+
+	ns, err := hostNetworkSystemFromHostSystemID(client, hostID)
+	if err != nil {
+		return err
+	}
+
+	spec := hostVirtualSwitchSpec(d)
+	if err := ns.AddVirtualSwitch(context.TODO(), name, &spec); err != nil {
+		return fmt.Errorf("error adding virtual switch %s to host %s: %s", name, hostID, err)
+	}
+	log.Printf("[INFO] Added virtual switch %s to host %s", name, hostID)
+
+	d.SetId(hostNetworkID(hostID, name))
+
+	return resourceVSphereHostVirtualSwitchRead(d, meta)
+}
+
+func resourceVSphereHostVirtualSwitchRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	hostID, name, err := splitHostNetworkID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	ns, err := hostNetworkSystemFromHostSystemID(client, hostID)
+	if err != nil {
+		return err
+	}
+	info, err := hostNetworkInfo(ns)
+	if err != nil {
+		return err
+	}
+
+	var vswitch *types.HostVirtualSwitch
+	for i := range info.Vswitch {
+		if info.Vswitch[i].Name == name {
+			vswitch = &info.Vswitch[i]
+			break
+		}
+	}
+	if vswitch == nil {
+		log.Printf("[DEBUG] virtual switch %s not found on host %s", name, hostID)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", name)
+	d.Set("host_system_id", hostID)
+	d.Set("number_of_ports", vswitch.Spec.NumPorts)
+	d.Set("mtu", vswitch.Mtu)
+	if bridge, ok := vswitch.Spec.Bridge.(*types.HostVirtualSwitchBondBridge); ok {
+		d.Set("network_adapters", bridge.NicDevice)
+	} else {
+		d.Set("network_adapters", []string{})
+	}
+
+	if policy := vswitch.Spec.Policy; policy != nil {
+		if teaming := policy.NicTeaming; teaming != nil {
+			d.Set("teaming_policy", teaming.Policy)
+			if teaming.NotifySwitches != nil {
+				d.Set("notify_switches", *teaming.NotifySwitches)
+			}
+			if teaming.RollingOrder != nil {
+				d.Set("failback", !*teaming.RollingOrder)
+			}
+			if teaming.NicOrder != nil {
+				d.Set("active_nics", teaming.NicOrder.ActiveNic)
+				d.Set("standby_nics", teaming.NicOrder.StandbyNic)
+			}
+		}
+		if security := policy.Security; security != nil {
+			setBool(d, "allow_promiscuous", security.AllowPromiscuous)
+			setBool(d, "allow_forged_transmits", security.ForgedTransmits)
+			setBool(d, "allow_mac_changes", security.MacChanges)
+		}
+	}
+
+	return nil
+}
+
+func resourceVSphereHostVirtualSwitchUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	hostID, name, err := splitHostNetworkID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	ns, err := hostNetworkSystemFromHostSystemID(client, hostID)
+	if err != nil {
+		return err
+	}
+
+	if err := ns.UpdateVirtualSwitch(context.TODO(), name, hostVirtualSwitchSpec(d)); err != nil {
+		return fmt.Errorf("error updating virtual switch %s on host %s: %s", name, hostID, err)
+	}
+
+	return resourceVSphereHostVirtualSwitchRead(d, meta)
+}
+
+func resourceVSphereHostVirtualSwitchDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	hostID, name, err := splitHostNetworkID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	ns, err := hostNetworkSystemFromHostSystemID(client, hostID)
+	if err != nil {
+		return err
+	}
+
+	if err := ns.RemoveVirtualSwitch(context.TODO(), name); err != nil {
+		return fmt.Errorf("error removing virtual switch %s from host %s: %s", name, hostID, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceVSphereHostVirtualSwitchImport imports a virtual switch using the
+// same "<host_system_id>:<name>" format as the resource ID.
+func resourceVSphereHostVirtualSwitchImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, _, err := splitHostNetworkID(d.Id()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func hostVirtualSwitchSpec(d *schema.ResourceData) types.HostVirtualSwitchSpec {
+	spec := types.HostVirtualSwitchSpec{
+		NumPorts: int32(d.Get("number_of_ports").(int)),
+		Mtu:      int32(d.Get("mtu").(int)),
+		Policy: &types.HostNetworkPolicy{
+			Security: &types.HostNetworkSecurityPolicy{
+				AllowPromiscuous: types.NewBool(d.Get("allow_promiscuous").(bool)),
+				ForgedTransmits:  types.NewBool(d.Get("allow_forged_transmits").(bool)),
+				MacChanges:       types.NewBool(d.Get("allow_mac_changes").(bool)),
+			},
+			NicTeaming: &types.HostNicTeamingPolicy{
+				Policy:         d.Get("teaming_policy").(string),
+				NotifySwitches: types.NewBool(d.Get("notify_switches").(bool)),
+				RollingOrder:   types.NewBool(!d.Get("failback").(bool)),
+				NicOrder: &types.HostNicOrderPolicy{
+					ActiveNic:  stringList(d.Get("active_nics").([]interface{})),
+					StandbyNic: stringList(d.Get("standby_nics").([]interface{})),
+				},
+			},
+		},
+	}
+
+	if nics := stringList(d.Get("network_adapters").([]interface{})); len(nics) > 0 {
+		spec.Bridge = &types.HostVirtualSwitchBondBridge{
+			NicDevice: nics,
+		}
+	}
+
+	return spec
+}
+
+// hostNetworkID builds the ID of a network object, such as a standard virtual
+// switch or port group, that is identified by its name on a host.
+func hostNetworkID(hostID, name string) string {
+	return hostID + ":" + name
+}
+
+// splitHostNetworkID splits an ID built by hostNetworkID into the ID of the
+// host and the name of the network object.
+func splitHostNetworkID(id string) (string, string, error) {
+	parts := strings.SplitN(id, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid ID %q, expected <host_system_id>:<name>", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+func hostNetworkSystemFromHostSystemID(client *govmomi.Client, hostID string) (*object.HostNetworkSystem, error) {
+	host := object.NewHostSystem(client.Client, hostSystemReference(hostID))
+	ns, err := host.ConfigManager().NetworkSystem(context.TODO())
+	if err != nil {
+		return nil, fmt.Errorf("error finding network system of host %s: %s", hostID, err)
+	}
+	return ns, nil
+}
+
+func hostNetworkInfo(ns *object.HostNetworkSystem) (*types.HostNetworkInfo, error) {
+	var mns mo.HostNetworkSystem
+	if err := ns.Properties(context.TODO(), ns.Reference(), []string{"networkInfo"}, &mns); err != nil {
+		return nil, err
+	}
+	if mns.NetworkInfo == nil {
+		return nil, fmt.Errorf("no network information for %s", ns.Reference().Value)
+	}
+	return mns.NetworkInfo, nil
+}
+
+// setBool sets key from a boolean pointer, if it is not nil.
+func setBool(d *schema.ResourceData, key string, v *bool) {
+	if v != nil {
+		d.Set(key, *v)
+	}
+}
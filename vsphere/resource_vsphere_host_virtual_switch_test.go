@@ -0,0 +1,150 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestAccVSphereHostVirtualSwitch_basic(t *testing.T) {
+	host := os.Getenv("VSPHERE_HOST_SYSTEM_ID")
+	nic := os.Getenv("VSPHERE_HOST_NIC")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccVSphereHostNetworkPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereHostVirtualSwitchDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereHostVirtualSwitchConfig, host, nic, nic, 1500, "false"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereHostVirtualSwitchExists("vsphere_host_virtual_switch.switch"),
+					resource.TestCheckResourceAttr("vsphere_host_virtual_switch.switch", "mtu", "1500"),
+					resource.TestCheckResourceAttr("vsphere_host_virtual_switch.switch", "network_adapters.#", "1"),
+					resource.TestCheckResourceAttr("vsphere_host_virtual_switch.switch", "allow_promiscuous", "false"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereHostVirtualSwitchConfig, host, nic, nic, 9000, "true"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereHostVirtualSwitchExists("vsphere_host_virtual_switch.switch"),
+					resource.TestCheckResourceAttr("vsphere_host_virtual_switch.switch", "mtu", "9000"),
+					resource.TestCheckResourceAttr("vsphere_host_virtual_switch.switch", "allow_promiscuous", "true"),
+				),
+			},
+			{
+				ResourceName:      "vsphere_host_virtual_switch.switch",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestSplitHostNetworkID(t *testing.T) {
+	host, name, err := splitHostNetworkID(hostNetworkID("host-10", "vSwitch1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if host != "host-10" || name != "vSwitch1" {
+		t.Fatalf("expected host-10 and vSwitch1, got %s and %s", host, name)
+	}
+
+	for _, id := range []string{"", "host-10", "host-10:", ":vSwitch1"} {
+		if _, _, err := splitHostNetworkID(id); err == nil {
+			t.Fatalf("expected error for ID %q", id)
+		}
+	}
+}
+
+func testAccVSphereHostNetworkPreCheck(t *testing.T) {
+	for _, k := range []string{"VSPHERE_HOST_SYSTEM_ID", "VSPHERE_HOST_NIC"} {
+		if v := os.Getenv(k); v == "" {
+			t.Fatalf("env variable %s must be set for acceptance tests", k)
+		}
+	}
+}
+
+func testAccCheckVSphereHostVirtualSwitchDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "vsphere_host_virtual_switch" {
+			continue
+		}
+
+		vswitch, err := testAccGetHostVirtualSwitch(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if vswitch != nil {
+			return fmt.Errorf("virtual switch %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckVSphereHostVirtualSwitchExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		vswitch, err := testAccGetHostVirtualSwitch(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if vswitch == nil {
+			return fmt.Errorf("virtual switch %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccGetHostVirtualSwitch(id string) (*types.HostVirtualSwitch, error) {
+	client := testAccProvider.Meta().(*govmomi.Client)
+	hostID, name, err := splitHostNetworkID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ns, err := hostNetworkSystemFromHostSystemID(client, hostID)
+	if err != nil {
+		return nil, err
+	}
+	info, err := hostNetworkInfo(ns)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, vswitch := range info.Vswitch {
+		if vswitch.Name == name {
+			return &vswitch, nil
+		}
+	}
+	return nil, nil
+}
+
+const testAccCheckVSphereHostVirtualSwitchConfig = `
+resource "vsphere_host_virtual_switch" "switch" {
+  name              = "vSwitchTerraformTest"
+  host_system_id    = "%s"
+  network_adapters  = ["%s"]
+  active_nics       = ["%s"]
+  mtu               = %d
+  allow_promiscuous = %s
+}
+`
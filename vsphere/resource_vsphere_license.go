@@ -32,6 +32,9 @@ func resourceVSphereLicense() *schema.Resource {
 		Read:   resourceVSphereLicenseRead,
 		Update: resourceVSphereLicenseUpdate,
 		Delete: resourceVSphereLicenseDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereLicenseImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"license_key": &schema.Schema{
@@ -196,6 +199,19 @@ func resourceVSphereLicenseDelete(d *schema.ResourceData, meta interface{}) erro
 	return ErrNoSuchKeyFound
 }
 
+// resourceVSphereLicenseImport imports a license using the license key as
+// the ID.
+func resourceVSphereLicenseImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*govmomi.Client)
+	manager := license.NewManager(client.Client)
+
+	if !isKeyPresent(d.Id(), manager) {
+		return nil, ErrNoSuchKeyFound
+	}
+	d.Set("license_key", d.Id())
+	return []*schema.ResourceData{d}, nil
+}
+
 func getLicenseInfoFromKey(key string, manager *license.Manager) *types.LicenseManagerLicenseInfo {
 	// Use of decode is not returning labels so using list instead
 	// Issue - https://github.com/vmware/govmomi/issues/797
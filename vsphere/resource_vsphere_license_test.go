@@ -42,6 +42,29 @@ func TestAccVSphereLicenseBasic(t *testing.T) {
 
 }
 
+func TestAccVSphereLicense_importBasic(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccVSpherePreLicenseBasicCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccVSphereLicenseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVSphereLicenseBasicConfig(),
+			},
+			{
+				ResourceName:      "vsphere_license.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+
+}
+
 func TestAccVSphereLicenseInvalid(t *testing.T) {
 
 	resource.Test(t, resource.TestCase{
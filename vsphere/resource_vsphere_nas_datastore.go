@@ -0,0 +1,305 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+var nasDatastoreTypes = []string{
+	string(types.HostFileSystemVolumeFileSystemTypeNFS),
+	string(types.HostFileSystemVolumeFileSystemTypeNFS41),
+}
+
+var nasDatastoreAccessModes = []string{
+	string(types.HostMountModeReadWrite),
+	string(types.HostMountModeReadOnly),
+}
+
+func resourceVSphereNasDatastore() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereNasDatastoreCreate,
+		Read:   resourceVSphereNasDatastoreRead,
+		Update: resourceVSphereNasDatastoreUpdate,
+		Delete: resourceVSphereNasDatastoreDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereNasDatastoreImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"host_system_ids": &schema.Schema{
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"type": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      string(types.HostFileSystemVolumeFileSystemTypeNFS),
+				ForceNew:     true,
+				ValidateFunc: validateStringInSlice(nasDatastoreTypes),
+			},
+
+			"remote_hosts": &schema.Schema{
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"remote_path": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"access_mode": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      string(types.HostMountModeReadWrite),
+				ForceNew:     true,
+				ValidateFunc: validateStringInSlice(nasDatastoreAccessModes),
+			},
+
+			"url": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			// Capacity in MB
+			"capacity": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			// Free space in MB
+			"free_space": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceVSphereNasDatastoreCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	spec := nasVolumeSpec(d)
+
+	for _, hostID := range d.Get("host_system_ids").(*schema.Set).List() {
+		ds, err := nasDatastoreMount(client, hostID.(string), spec)
+		if err != nil {
+			return err
+		}
+		d.SetId(ds.Reference().Value)
+	}
+	log.Printf("[INFO] Created NAS datastore %s", d.Id())
+
+	return resourceVSphereNasDatastoreRead(d, meta)
+}
+
+func resourceVSphereNasDatastoreRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	mds, err := datastoreProperties(client, d.Id())
+	if err != nil {
+		if isManagedObjectNotFoundError(err) {
+			log.Printf("[DEBUG] NAS datastore %s not found", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("error reading NAS datastore %s: %s", d.Id(), err)
+	}
+
+	info, ok := mds.Info.(*types.NasDatastoreInfo)
+	if !ok || info.Nas == nil {
+		return fmt.Errorf("datastore %s is not a NAS datastore", d.Id())
+	}
+
+	d.Set("name", mds.Summary.Name)
+	d.Set("type", info.Nas.Type)
+	d.Set("remote_path", info.Nas.RemotePath)
+	remoteHosts := info.Nas.RemoteHostNames
+	if len(remoteHosts) == 0 {
+		remoteHosts = []string{info.Nas.RemoteHost}
+	}
+	if err := d.Set("remote_hosts", remoteHosts); err != nil {
+		return fmt.Errorf("Invalid remote_hosts to set: %#v", remoteHosts)
+	}
+
+	hostIDs := make([]string, 0, len(mds.Host))
+	for _, mount := range mds.Host {
+		hostIDs = append(hostIDs, mount.Key.Value)
+		d.Set("access_mode", mount.MountInfo.AccessMode)
+	}
+	if err := d.Set("host_system_ids", hostIDs); err != nil {
+		return fmt.Errorf("Invalid host_system_ids to set: %#v", hostIDs)
+	}
+
+	d.Set("url", mds.Summary.Url)
+	d.Set("capacity", mds.Summary.Capacity/1024/1024)
+	d.Set("free_space", mds.Summary.FreeSpace/1024/1024)
+
+	return nil
+}
+
+func resourceVSphereNasDatastoreUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	if d.HasChange("name") {
+		if err := datastoreRename(client, d.Id(), d.Get("name").(string)); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("host_system_ids") {
+		o, n := d.GetChange("host_system_ids")
+		oldHosts := o.(*schema.Set)
+		newHosts := n.(*schema.Set)
+
+		spec := nasVolumeSpec(d)
+		for _, hostID := range newHosts.Difference(oldHosts).List() {
+			if _, err := nasDatastoreMount(client, hostID.(string), spec); err != nil {
+				return err
+			}
+		}
+		for _, hostID := range oldHosts.Difference(newHosts).List() {
+			if err := datastoreUnmount(client, hostID.(string), d.Id()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return resourceVSphereNasDatastoreRead(d, meta)
+}
+
+func resourceVSphereNasDatastoreDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	// The datastore is destroyed once it is unmounted from its last host.
+	for _, hostID := range d.Get("host_system_ids").(*schema.Set).List() {
+		if err := datastoreUnmount(client, hostID.(string), d.Id()); err != nil {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceVSphereNasDatastoreImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	return datastoreImport(d, meta)
+}
+
+// nasVolumeSpec builds the specification used to mount the share on each
+// host. Only NFS 4.1 supports multiple remote hosts.
+func nasVolumeSpec(d *schema.ResourceData) types.HostNasVolumeSpec {
+	remoteHosts := stringList(d.Get("remote_hosts").([]interface{}))
+	spec := types.HostNasVolumeSpec{
+		RemoteHost: remoteHosts[0],
+		RemotePath: d.Get("remote_path").(string),
+		LocalPath:  d.Get("name").(string),
+		AccessMode: d.Get("access_mode").(string),
+		Type:       d.Get("type").(string),
+	}
+	if spec.Type == string(types.HostFileSystemVolumeFileSystemTypeNFS41) {
+		spec.RemoteHostNames = remoteHosts
+	}
+	return spec
+}
+
+// nasDatastoreMount mounts a NAS share on a host. Mounting a share that is
+// already mounted on another host returns the existing datastore.
+func nasDatastoreMount(client *govmomi.Client, hostID string, spec types.HostNasVolumeSpec) (*object.Datastore, error) {
+	dss, err := hostDatastoreSystemFromHostSystemID(client, hostID)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := dss.CreateNasDatastore(context.TODO(), spec)
+	if err != nil {
+		return nil, fmt.Errorf("error mounting NAS datastore %s on host %s: %s", spec.LocalPath, hostID, err)
+	}
+	return ds, nil
+}
+
+func hostDatastoreSystemFromHostSystemID(client *govmomi.Client, hostID string) (*object.HostDatastoreSystem, error) {
+	host := object.NewHostSystem(client.Client, hostSystemReference(hostID))
+	dss, err := host.ConfigManager().DatastoreSystem(context.TODO())
+	if err != nil {
+		return nil, fmt.Errorf("error finding datastore system of host %s: %s", hostID, err)
+	}
+	return dss, nil
+}
+
+// datastoreUnmount removes a datastore from a host. For VMFS datastores, or
+// NAS datastores mounted on a single host, this destroys the datastore.
+func datastoreUnmount(client *govmomi.Client, hostID, id string) error {
+	dss, err := hostDatastoreSystemFromHostSystemID(client, hostID)
+	if err != nil {
+		return err
+	}
+	ds := object.NewDatastore(client.Client, datastoreReference(id))
+	if err := dss.Remove(context.TODO(), ds); err != nil {
+		return fmt.Errorf("error removing datastore %s from host %s: %s", id, hostID, err)
+	}
+	return nil
+}
+
+func datastoreRename(client *govmomi.Client, id, name string) error {
+	req := &types.RenameDatastore{
+		This:    datastoreReference(id),
+		NewName: name,
+	}
+	if _, err := methods.RenameDatastore(context.TODO(), client, req); err != nil {
+		return fmt.Errorf("error renaming datastore %s: %s", id, err)
+	}
+	return nil
+}
+
+// datastoreImport imports a datastore using its inventory path, for example
+// /dc1/datastore/nfs1.
+func datastoreImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*govmomi.Client)
+
+	ref, err := object.NewSearchIndex(client.Client).FindByInventoryPath(context.TODO(), d.Id())
+	if err != nil {
+		return nil, err
+	}
+	ds, ok := ref.(*object.Datastore)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a datastore", d.Id())
+	}
+
+	d.SetId(ds.Reference().Value)
+	return []*schema.ResourceData{d}, nil
+}
+
+func datastoreReference(id string) types.ManagedObjectReference {
+	return types.ManagedObjectReference{
+		Type:  "Datastore",
+		Value: id,
+	}
+}
+
+func datastoreProperties(client *govmomi.Client, id string) (*mo.Datastore, error) {
+	var mds mo.Datastore
+	collector := property.DefaultCollector(client.Client)
+	if err := collector.RetrieveOne(context.TODO(), datastoreReference(id), []string{"summary", "info", "host"}, &mds); err != nil {
+		return nil, err
+	}
+	return &mds, nil
+}
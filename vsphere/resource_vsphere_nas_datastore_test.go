@@ -0,0 +1,108 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/vmware/govmomi"
+)
+
+func TestAccVSphereNasDatastore_basic(t *testing.T) {
+	host := os.Getenv("VSPHERE_HOST_SYSTEM_ID")
+	nasHost := os.Getenv("VSPHERE_NAS_HOST")
+	nasPath := os.Getenv("VSPHERE_NAS_PATH")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccVSphereNasDatastorePreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereDatastoreDestroy("vsphere_nas_datastore"),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereNasDatastoreConfig, "terraform-test-nfs", host, nasHost, nasPath),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereDatastoreExists("vsphere_nas_datastore.nfs"),
+					resource.TestCheckResourceAttr("vsphere_nas_datastore.nfs", "type", "NFS"),
+					resource.TestCheckResourceAttr("vsphere_nas_datastore.nfs", "access_mode", "readWrite"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereNasDatastoreConfig, "terraform-test-nfs-renamed", host, nasHost, nasPath),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereDatastoreExists("vsphere_nas_datastore.nfs"),
+					resource.TestCheckResourceAttr("vsphere_nas_datastore.nfs", "name", "terraform-test-nfs-renamed"),
+				),
+			},
+			{
+				ResourceName:      "vsphere_nas_datastore.nfs",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("/%s/datastore/terraform-test-nfs-renamed", os.Getenv("VSPHERE_DATACENTER")),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccVSphereNasDatastorePreCheck(t *testing.T) {
+	for _, k := range []string{"VSPHERE_DATACENTER", "VSPHERE_HOST_SYSTEM_ID", "VSPHERE_NAS_HOST", "VSPHERE_NAS_PATH"} {
+		if v := os.Getenv(k); v == "" {
+			t.Fatalf("env variable %s must be set for acceptance tests", k)
+		}
+	}
+}
+
+func testAccCheckVSphereDatastoreDestroy(resourceType string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*govmomi.Client)
+
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+
+			_, err := datastoreProperties(client, rs.Primary.ID)
+			if err == nil {
+				return fmt.Errorf("datastore %s still exists", rs.Primary.ID)
+			}
+			if !isManagedObjectNotFoundError(err) {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckVSphereDatastoreExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*govmomi.Client)
+		if _, err := datastoreProperties(client, rs.Primary.ID); err != nil {
+			return fmt.Errorf("error reading datastore %s: %s", rs.Primary.ID, err)
+		}
+
+		return nil
+	}
+}
+
+const testAccCheckVSphereNasDatastoreConfig = `
+resource "vsphere_nas_datastore" "nfs" {
+  name            = "%s"
+  host_system_ids = ["%s"]
+  remote_hosts    = ["%s"]
+  remote_path     = "%s"
+}
+`
@@ -0,0 +1,283 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+var sharesLevels = []string{
+	string(types.SharesLevelLow),
+	string(types.SharesLevelNormal),
+	string(types.SharesLevelHigh),
+	string(types.SharesLevelCustom),
+}
+
+func resourceVSphereResourcePool() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"name": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+		},
+
+		"parent_resource_pool_id": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+	}
+	for k, v := range resourceAllocationSchema("cpu") {
+		s[k] = v
+	}
+	for k, v := range resourceAllocationSchema("memory") {
+		s[k] = v
+	}
+
+	return &schema.Resource{
+		Create: resourceVSphereResourcePoolCreate,
+		Read:   resourceVSphereResourcePoolRead,
+		Update: resourceVSphereResourcePoolUpdate,
+		Delete: resourceVSphereResourcePoolDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereResourcePoolImport,
+		},
+
+		Schema: s,
+	}
+}
+
+// resourceAllocationSchema returns the shares, reservation, expandable
+// reservation and limit arguments of a resource allocation, prefixed with the
+// name of the resource they apply to. Reservations and limits are in MHz for
+// CPU and in MB for memory.
+func resourceAllocationSchema(prefix string) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		prefix + "_share_level": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      string(types.SharesLevelNormal),
+			ValidateFunc: validateStringInSlice(sharesLevels),
+		},
+
+		prefix + "_shares": &schema.Schema{
+			Type:     schema.TypeInt,
+			Optional: true,
+			Computed: true,
+		},
+
+		prefix + "_reservation": &schema.Schema{
+			Type:     schema.TypeInt,
+			Optional: true,
+			Default:  0,
+		},
+
+		prefix + "_expandable": &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+
+		prefix + "_limit": &schema.Schema{
+			Type:     schema.TypeInt,
+			Optional: true,
+			Default:  -1,
+		},
+	}
+}
+
+func resourceVSphereResourcePoolCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	parentRef := types.ManagedObjectReference{
+		Type:  "ResourcePool",
+		Value: d.Get("parent_resource_pool_id").(string),
+	}
+	parent := object.NewResourcePool(client.Client, parentRef)
+
+	name := d.Get("name").(string)
+	rp, err := parent.Create(context.TODO(), name, resourcePoolConfigSpec(d))
+	if err != nil {
+		return fmt.Errorf("error creating resource pool %s: %s", name, err)
+	}
+	log.Printf("[INFO] Created resource pool: %s", rp.Reference().Value)
+
+	d.SetId(rp.Reference().Value)
+
+	return resourceVSphereResourcePoolRead(d, meta)
+}
+
+func resourceVSphereResourcePoolRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	var mrp mo.ResourcePool
+	collector := property.DefaultCollector(client.Client)
+	if err := collector.RetrieveOne(context.TODO(), resourcePoolReference(d.Id()), []string{"name", "parent", "config"}, &mrp); err != nil {
+		if isManagedObjectNotFoundError(err) {
+			log.Printf("[DEBUG] resource pool %s not found: %s", d.Id(), err)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("name", mrp.Name)
+	if mrp.Parent != nil {
+		d.Set("parent_resource_pool_id", mrp.Parent.Value)
+	}
+	flattenResourceAllocation(d, "cpu", mrp.Config.CpuAllocation.GetResourceAllocationInfo())
+	flattenResourceAllocation(d, "memory", mrp.Config.MemoryAllocation.GetResourceAllocationInfo())
+
+	return nil
+}
+
+func resourceVSphereResourcePoolUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	rp := object.NewResourcePool(client.Client, resourcePoolReference(d.Id()))
+
+	var name string
+	if d.HasChange("name") {
+		name = d.Get("name").(string)
+	}
+	spec := resourcePoolConfigSpec(d)
+	if err := rp.UpdateConfig(context.TODO(), name, &spec); err != nil {
+		return fmt.Errorf("error updating resource pool %s: %s", d.Id(), err)
+	}
+
+	return resourceVSphereResourcePoolRead(d, meta)
+}
+
+func resourceVSphereResourcePoolDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	rp := object.NewResourcePool(client.Client, resourcePoolReference(d.Id()))
+
+	task, err := rp.Destroy(context.TODO())
+	if err != nil {
+		return fmt.Errorf("error destroying resource pool %s: %s", d.Id(), err)
+	}
+	if err := task.Wait(context.TODO()); err != nil {
+		return fmt.Errorf("error destroying resource pool %s: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceVSphereResourcePoolImport imports a resource pool by its inventory
+// path, such as "/dc1/host/cluster1/Resources/pool1".
+func resourceVSphereResourcePoolImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*govmomi.Client)
+
+	ref, err := object.NewSearchIndex(client.Client).FindByInventoryPath(context.TODO(), d.Id())
+	if err != nil {
+		return nil, err
+	}
+	rp, ok := ref.(*object.ResourcePool)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a resource pool", d.Id())
+	}
+
+	d.SetId(rp.Reference().Value)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourcePoolReference(id string) types.ManagedObjectReference {
+	return types.ManagedObjectReference{
+		Type:  "ResourcePool",
+		Value: id,
+	}
+}
+
+func resourcePoolConfigSpec(d *schema.ResourceData) types.ResourceConfigSpec {
+	return types.ResourceConfigSpec{
+		CpuAllocation:    expandResourceAllocation(d, "cpu"),
+		MemoryAllocation: expandResourceAllocation(d, "memory"),
+	}
+}
+
+// ResourceAllocationInfo is a types.ResourceAllocationInfo that always sends
+// its reservation and limit. The vSphere SDK type omits zero values from
+// requests, which makes it impossible to reduce a reservation or limit to 0.
+// The type keeps the name of the vSphere type, as the SOAP encoder uses the Go
+// type name for the xsi:type attribute.
+type ResourceAllocationInfo struct {
+	types.DynamicData
+
+	Reservation           int64             `xml:"reservation"`
+	ExpandableReservation *bool             `xml:"expandableReservation"`
+	Limit                 int64             `xml:"limit"`
+	Shares                *types.SharesInfo `xml:"shares,omitempty"`
+}
+
+// GetResourceAllocationInfo implements types.BaseResourceAllocationInfo.
+func (r *ResourceAllocationInfo) GetResourceAllocationInfo() *types.ResourceAllocationInfo {
+	return &types.ResourceAllocationInfo{
+		DynamicData:           r.DynamicData,
+		Reservation:           r.Reservation,
+		ExpandableReservation: r.ExpandableReservation,
+		Limit:                 r.Limit,
+		Shares:                r.Shares,
+	}
+}
+
+// expandResourceAllocation builds a ResourceAllocationInfo from the arguments
+// defined by resourceAllocationSchema.
+func expandResourceAllocation(d *schema.ResourceData, prefix string) *ResourceAllocationInfo {
+	return &ResourceAllocationInfo{
+		Reservation:           int64(d.Get(prefix + "_reservation").(int)),
+		ExpandableReservation: types.NewBool(d.Get(prefix + "_expandable").(bool)),
+		Limit:                 int64(d.Get(prefix + "_limit").(int)),
+		Shares: &types.SharesInfo{
+			Level:  types.SharesLevel(d.Get(prefix + "_share_level").(string)),
+			Shares: int32(d.Get(prefix + "_shares").(int)),
+		},
+	}
+}
+
+// flattenResourceAllocation sets the arguments defined by
+// resourceAllocationSchema from a ResourceAllocationInfo.
+func flattenResourceAllocation(d *schema.ResourceData, prefix string, info *types.ResourceAllocationInfo) {
+	d.Set(prefix+"_reservation", info.Reservation)
+	d.Set(prefix+"_limit", info.Limit)
+	if info.ExpandableReservation != nil {
+		d.Set(prefix+"_expandable", *info.ExpandableReservation)
+	}
+	if info.Shares != nil {
+		d.Set(prefix+"_share_level", string(info.Shares.Level))
+		d.Set(prefix+"_shares", info.Shares.Shares)
+	}
+}
+
+// isManagedObjectNotFoundError returns true if err is the fault returned by
+// vSphere when a managed object reference no longer exists.
+func isManagedObjectNotFoundError(err error) bool {
+	if !soap.IsSoapFault(err) {
+		return false
+	}
+	_, ok := soap.ToSoapFault(err).VimFault().(types.ManagedObjectNotFound)
+	return ok
+}
+
+// validateStringInSlice returns a ValidateFunc that accepts only the given
+// values.
+func validateStringInSlice(valid []string) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) (ws []string, errors []error) {
+		value := v.(string)
+		for _, s := range valid {
+			if value == s {
+				return
+			}
+		}
+		errors = append(errors, fmt.Errorf(
+			"only %s are supported values for '%s'", strings.Join(valid, ", "), k))
+		return
+	}
+}
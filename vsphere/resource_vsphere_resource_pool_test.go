@@ -0,0 +1,224 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/govmomi/vim25/xml"
+	"golang.org/x/net/context"
+)
+
+func TestAccVSphereResourcePool_basic(t *testing.T) {
+	parent := os.Getenv("VSPHERE_RESOURCE_POOL_ID")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccVSphereResourcePoolPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereResourcePoolDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereResourcePoolConfig, parent),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereResourcePoolExists("vsphere_resource_pool.parent"),
+					testAccCheckVSphereResourcePoolExists("vsphere_resource_pool.child"),
+					resource.TestCheckResourceAttrPair("vsphere_resource_pool.child", "parent_resource_pool_id", "vsphere_resource_pool.parent", "id"),
+					resource.TestCheckResourceAttr("vsphere_resource_pool.parent", "cpu_share_level", "normal"),
+					resource.TestCheckResourceAttr("vsphere_resource_pool.parent", "cpu_limit", "-1"),
+					resource.TestCheckResourceAttr("vsphere_resource_pool.child", "memory_share_level", "custom"),
+					resource.TestCheckResourceAttr("vsphere_resource_pool.child", "memory_shares", "8000"),
+					resource.TestCheckResourceAttr("vsphere_resource_pool.child", "memory_reservation", "256"),
+					resource.TestCheckResourceAttr("vsphere_resource_pool.child", "memory_expandable", "false"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereResourcePoolConfigUpdated, parent),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereResourcePoolExists("vsphere_resource_pool.child"),
+					resource.TestCheckResourceAttr("vsphere_resource_pool.child", "name", "terraform-test-child-renamed"),
+					resource.TestCheckResourceAttr("vsphere_resource_pool.child", "cpu_share_level", "high"),
+					resource.TestCheckResourceAttr("vsphere_resource_pool.child", "cpu_limit", "2000"),
+					resource.TestCheckResourceAttr("vsphere_resource_pool.child", "memory_share_level", "low"),
+					resource.TestCheckResourceAttr("vsphere_resource_pool.child", "memory_reservation", "512"),
+					resource.TestCheckResourceAttr("vsphere_resource_pool.child", "memory_expandable", "true"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereResourcePoolConfigNoReservation, parent),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereResourcePoolExists("vsphere_resource_pool.child"),
+					resource.TestCheckResourceAttr("vsphere_resource_pool.child", "memory_reservation", "0"),
+					resource.TestCheckResourceAttr("vsphere_resource_pool.child", "cpu_limit", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestExpandResourceAllocation_zeroReservation(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceVSphereResourcePool().Schema, map[string]interface{}{
+		"memory_reservation": 0,
+		"memory_limit":       0,
+	})
+	spec := types.ResourceConfigSpec{
+		CpuAllocation:    expandResourceAllocation(d, "cpu"),
+		MemoryAllocation: expandResourceAllocation(d, "memory"),
+	}
+
+	b, err := xml.Marshal(spec)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	out := string(b)
+	expected := []string{
+		`type="ResourceAllocationInfo"><reservation>0</reservation>`,
+		`<reservation>0</reservation><expandableReservation>true</expandableReservation><limit>0</limit>`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(out, e) {
+			t.Fatalf("expected %s in %s", e, out)
+		}
+	}
+}
+
+func TestAccVSphereResourcePool_importBasic(t *testing.T) {
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+	cluster := os.Getenv("VSPHERE_CLUSTER")
+	parent := os.Getenv("VSPHERE_RESOURCE_POOL_ID")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccVSphereResourcePoolPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereResourcePoolDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereResourcePoolConfig, parent),
+			},
+			{
+				ResourceName:      "vsphere_resource_pool.parent",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("/%s/host/%s/Resources/terraform-test-parent", datacenter, cluster),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccVSphereResourcePoolPreCheck(t *testing.T) {
+	if v := os.Getenv("VSPHERE_RESOURCE_POOL_ID"); v == "" {
+		t.Fatal("env variable VSPHERE_RESOURCE_POOL_ID must be set to the ID of the root resource pool of VSPHERE_CLUSTER for acceptance tests")
+	}
+}
+
+func testAccCheckVSphereResourcePoolDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*govmomi.Client)
+	collector := property.DefaultCollector(client.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "vsphere_resource_pool" {
+			continue
+		}
+
+		var mrp mo.ResourcePool
+		err := collector.RetrieveOne(context.TODO(), resourcePoolReference(rs.Primary.ID), []string{"name"}, &mrp)
+		if err == nil {
+			return fmt.Errorf("resource pool %s still exists", rs.Primary.ID)
+		}
+		if !isManagedObjectNotFoundError(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckVSphereResourcePoolExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*govmomi.Client)
+		collector := property.DefaultCollector(client.Client)
+
+		var mrp mo.ResourcePool
+		if err := collector.RetrieveOne(context.TODO(), resourcePoolReference(rs.Primary.ID), []string{"name"}, &mrp); err != nil {
+			return fmt.Errorf("error finding resource pool %s: %s", rs.Primary.ID, err)
+		}
+		if mrp.Name != rs.Primary.Attributes["name"] {
+			return fmt.Errorf("expected resource pool name %s, got %s", rs.Primary.Attributes["name"], mrp.Name)
+		}
+
+		return nil
+	}
+}
+
+const testAccCheckVSphereResourcePoolConfig = `
+resource "vsphere_resource_pool" "parent" {
+  name                    = "terraform-test-parent"
+  parent_resource_pool_id = "%s"
+}
+
+resource "vsphere_resource_pool" "child" {
+  name                    = "terraform-test-child"
+  parent_resource_pool_id = "${vsphere_resource_pool.parent.id}"
+
+  memory_share_level = "custom"
+  memory_shares      = 8000
+  memory_reservation = 256
+  memory_expandable  = false
+}
+`
+
+const testAccCheckVSphereResourcePoolConfigUpdated = `
+resource "vsphere_resource_pool" "parent" {
+  name                    = "terraform-test-parent"
+  parent_resource_pool_id = "%s"
+}
+
+resource "vsphere_resource_pool" "child" {
+  name                    = "terraform-test-child-renamed"
+  parent_resource_pool_id = "${vsphere_resource_pool.parent.id}"
+
+  cpu_share_level    = "high"
+  cpu_limit          = 2000
+  memory_share_level = "low"
+  memory_reservation = 512
+}
+`
+
+const testAccCheckVSphereResourcePoolConfigNoReservation = `
+resource "vsphere_resource_pool" "parent" {
+  name                    = "terraform-test-parent"
+  parent_resource_pool_id = "%s"
+}
+
+resource "vsphere_resource_pool" "child" {
+  name                    = "terraform-test-child-renamed"
+  parent_resource_pool_id = "${vsphere_resource_pool.parent.id}"
+
+  cpu_share_level    = "high"
+  cpu_limit          = 0
+  memory_share_level = "low"
+  memory_reservation = 0
+}
+`
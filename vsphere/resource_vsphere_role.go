@@ -0,0 +1,171 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"golang.org/x/net/context"
+)
+
+// defaultRolePrivileges are added by vSphere to every role, so they are left
+// out of privileges to avoid spurious diffs unless they are configured.
+var defaultRolePrivileges = []string{
+	"System.Anonymous",
+	"System.Read",
+	"System.View",
+}
+
+func resourceVSphereRole() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereRoleCreate,
+		Read:   resourceVSphereRoleRead,
+		Update: resourceVSphereRoleUpdate,
+		Delete: resourceVSphereRoleDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereRoleImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"privileges": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceVSphereRoleCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	m := object.NewAuthorizationManager(client.Client)
+
+	name := d.Get("name").(string)
+	id, err := m.AddRole(context.TODO(), name, stringList(d.Get("privileges").(*schema.Set).List()))
+	if err != nil {
+		return fmt.Errorf("error creating role %s: %s", name, err)
+	}
+	log.Printf("[INFO] Created role %s: %d", name, id)
+
+	d.SetId(strconv.Itoa(int(id)))
+
+	return resourceVSphereRoleRead(d, meta)
+}
+
+func resourceVSphereRoleRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	m := object.NewAuthorizationManager(client.Client)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("invalid role ID %q: %s", d.Id(), err)
+	}
+
+	roles, err := m.RoleList(context.TODO())
+	if err != nil {
+		return fmt.Errorf("error reading roles: %s", err)
+	}
+	role := roles.ById(int32(id))
+	if role == nil {
+		log.Printf("[DEBUG] role %s not found", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", role.Name)
+
+	privileges := rolePrivileges(role.Privilege, d.Get("privileges").(*schema.Set))
+	if err := d.Set("privileges", privileges); err != nil {
+		return fmt.Errorf("Invalid privileges to set: %#v", privileges)
+	}
+
+	return nil
+}
+
+func resourceVSphereRoleUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	m := object.NewAuthorizationManager(client.Client)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("invalid role ID %q: %s", d.Id(), err)
+	}
+
+	// UpdateRole replaces the privileges of the role, and keeps them when
+	// none are given, so the default privileges are sent when all the others
+	// are removed.
+	privileges := stringList(d.Get("privileges").(*schema.Set).List())
+	if len(privileges) == 0 {
+		privileges = defaultRolePrivileges
+	}
+
+	if err := m.UpdateRole(context.TODO(), int32(id), d.Get("name").(string), privileges); err != nil {
+		return fmt.Errorf("error updating role %s: %s", d.Id(), err)
+	}
+
+	return resourceVSphereRoleRead(d, meta)
+}
+
+func resourceVSphereRoleDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	m := object.NewAuthorizationManager(client.Client)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("invalid role ID %q: %s", d.Id(), err)
+	}
+
+	if err := m.RemoveRole(context.TODO(), int32(id), false); err != nil {
+		return fmt.Errorf("error removing role %s: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceVSphereRoleImport imports a role by its name.
+func resourceVSphereRoleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*govmomi.Client)
+	m := object.NewAuthorizationManager(client.Client)
+
+	roles, err := m.RoleList(context.TODO())
+	if err != nil {
+		return nil, fmt.Errorf("error reading roles: %s", err)
+	}
+	role := roles.ByName(d.Id())
+	if role == nil {
+		return nil, fmt.Errorf("role %s not found", d.Id())
+	}
+
+	d.SetId(strconv.Itoa(int(role.RoleId)))
+	return []*schema.ResourceData{d}, nil
+}
+
+func isDefaultRolePrivilege(privilege string) bool {
+	for _, p := range defaultRolePrivileges {
+		if p == privilege {
+			return true
+		}
+	}
+	return false
+}
+
+// rolePrivileges returns the privileges of a role to set in privileges,
+// without the default privileges that are not in configured.
+func rolePrivileges(privileges []string, configured *schema.Set) []string {
+	result := make([]string, 0, len(privileges))
+	for _, p := range privileges {
+		if !isDefaultRolePrivilege(p) || configured.Contains(p) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
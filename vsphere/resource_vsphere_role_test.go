@@ -0,0 +1,130 @@
+package vsphere
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"golang.org/x/net/context"
+)
+
+func TestAccVSphereRole_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckVSphereRoleConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereRoleExists("vsphere_role.role"),
+					resource.TestCheckResourceAttr("vsphere_role.role", "privileges.#", "2"),
+				),
+			},
+			{
+				Config: testAccCheckVSphereRoleConfigUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereRoleExists("vsphere_role.role"),
+					resource.TestCheckResourceAttr("vsphere_role.role", "name", "terraform-test-role-renamed"),
+					resource.TestCheckResourceAttr("vsphere_role.role", "privileges.#", "1"),
+				),
+			},
+			{
+				ResourceName:      "vsphere_role.role",
+				ImportState:       true,
+				ImportStateId:     "terraform-test-role-renamed",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckVSphereRoleDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "vsphere_role" {
+			continue
+		}
+
+		found, err := testAccRoleExists(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if found {
+			return fmt.Errorf("role %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckVSphereRoleExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		found, err := testAccRoleExists(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("role %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccRoleExists(id string) (bool, error) {
+	client := testAccProvider.Meta().(*govmomi.Client)
+	roleID, err := strconv.Atoi(id)
+	if err != nil {
+		return false, err
+	}
+
+	roles, err := object.NewAuthorizationManager(client.Client).RoleList(context.TODO())
+	if err != nil {
+		return false, err
+	}
+	return roles.ById(int32(roleID)) != nil, nil
+}
+
+const testAccCheckVSphereRoleConfig = `
+resource "vsphere_role" "role" {
+  name       = "terraform-test-role"
+  privileges = ["VirtualMachine.Interact.PowerOn", "VirtualMachine.Interact.PowerOff"]
+}
+`
+
+const testAccCheckVSphereRoleConfigUpdated = `
+resource "vsphere_role" "role" {
+  name       = "terraform-test-role-renamed"
+  privileges = ["VirtualMachine.Interact.PowerOn"]
+}
+`
+
+func TestRolePrivileges(t *testing.T) {
+	configured := schema.NewSet(schema.HashString, []interface{}{"System.Read", "VirtualMachine.Interact.PowerOn"})
+	privileges := rolePrivileges([]string{
+		"System.Anonymous",
+		"System.Read",
+		"System.View",
+		"VirtualMachine.Interact.PowerOn",
+	}, configured)
+
+	expected := []string{"System.Read", "VirtualMachine.Interact.PowerOn"}
+	if !reflect.DeepEqual(privileges, expected) {
+		t.Fatalf("expected %v, got %v", expected, privileges)
+	}
+}
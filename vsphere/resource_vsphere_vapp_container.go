@@ -0,0 +1,580 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+var vAppStartActions = []string{
+	string(types.VAppAutoStartActionNone),
+	string(types.VAppAutoStartActionPowerOn),
+}
+
+var vAppStopActions = []string{
+	string(types.VAppAutoStartActionNone),
+	string(types.VAppAutoStartActionPowerOff),
+	string(types.VAppAutoStartActionGuestShutdown),
+	string(types.VAppAutoStartActionSuspend),
+}
+
+func resourceVSphereVAppContainer() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"name": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+		},
+
+		"parent_resource_pool_id": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+
+		"parent_folder_id": &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+			ForceNew: true,
+		},
+
+		"entity": &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"target_id": &schema.Schema{
+						Type:     schema.TypeString,
+						Required: true,
+					},
+
+					"start_order": &schema.Schema{
+						Type:     schema.TypeInt,
+						Optional: true,
+						Default:  1,
+					},
+
+					"start_delay": &schema.Schema{
+						Type:     schema.TypeInt,
+						Optional: true,
+						Default:  120,
+					},
+
+					"start_action": &schema.Schema{
+						Type:         schema.TypeString,
+						Optional:     true,
+						Default:      string(types.VAppAutoStartActionPowerOn),
+						ValidateFunc: validateStringInSlice(vAppStartActions),
+					},
+
+					"wait_for_guest": &schema.Schema{
+						Type:     schema.TypeBool,
+						Optional: true,
+						Default:  false,
+					},
+
+					"stop_delay": &schema.Schema{
+						Type:     schema.TypeInt,
+						Optional: true,
+						Default:  120,
+					},
+
+					"stop_action": &schema.Schema{
+						Type:         schema.TypeString,
+						Optional:     true,
+						Default:      string(types.VAppAutoStartActionPowerOff),
+						ValidateFunc: validateStringInSlice(vAppStopActions),
+					},
+				},
+			},
+		},
+
+		"product": &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+
+					"vendor": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+
+					"version": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+
+					"full_version": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+
+					"vendor_url": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+
+					"product_url": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+
+					"app_url": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+				},
+			},
+		},
+
+		"properties": &schema.Schema{
+			Type:     schema.TypeMap,
+			Optional: true,
+		},
+	}
+	for k, v := range resourceAllocationSchema("cpu") {
+		s[k] = v
+	}
+	for k, v := range resourceAllocationSchema("memory") {
+		s[k] = v
+	}
+
+	return &schema.Resource{
+		Create: resourceVSphereVAppContainerCreate,
+		Read:   resourceVSphereVAppContainerRead,
+		Update: resourceVSphereVAppContainerUpdate,
+		Delete: resourceVSphereVAppContainerDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereVAppContainerImport,
+		},
+
+		Schema: s,
+	}
+}
+
+func resourceVSphereVAppContainerCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	parentRef, err := vAppParentReference(client, d.Get("parent_resource_pool_id").(string))
+	if err != nil {
+		return fmt.Errorf("error finding parent resource pool: %s", err)
+	}
+	parent := object.NewResourcePool(client.Client, parentRef)
+
+	// A child vApp lives in its parent vApp, every other vApp needs a VM
+	// folder.
+	var folder *object.Folder
+	if v, ok := d.GetOk("parent_folder_id"); ok {
+		folder = object.NewFolder(client.Client, folderReferenceFromID(v.(string)))
+	} else if parentRef.Type != "VirtualApp" {
+		ref, err := vmFolderForEntity(client, parentRef)
+		if err != nil {
+			return fmt.Errorf("error finding VM folder for vApp: %s", err)
+		}
+		folder = object.NewFolder(client.Client, ref)
+	}
+
+	name := d.Get("name").(string)
+	vapp, err := parent.CreateVApp(context.TODO(), name, resourcePoolConfigSpec(d), types.VAppConfigSpec{}, folder)
+	if err != nil {
+		return fmt.Errorf("error creating vApp %s: %s", name, err)
+	}
+	log.Printf("[INFO] Created vApp: %s", vapp.Reference().Value)
+
+	d.SetId(vapp.Reference().Value)
+
+	_, hasEntity := d.GetOk("entity")
+	_, hasProduct := d.GetOk("product")
+	_, hasProperties := d.GetOk("properties")
+	if hasEntity || hasProduct || hasProperties {
+		if err := vAppContainerConfigure(client, vapp, d); err != nil {
+			return err
+		}
+	}
+
+	return resourceVSphereVAppContainerRead(d, meta)
+}
+
+func resourceVSphereVAppContainerRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	var mva mo.VirtualApp
+	collector := property.DefaultCollector(client.Client)
+	if err := collector.RetrieveOne(context.TODO(), vAppReference(d.Id()), []string{"name", "parent", "parentFolder", "config", "vAppConfig"}, &mva); err != nil {
+		if isManagedObjectNotFoundError(err) {
+			log.Printf("[DEBUG] vApp %s not found: %s", d.Id(), err)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("name", mva.Name)
+	if mva.Parent != nil {
+		d.Set("parent_resource_pool_id", mva.Parent.Value)
+	}
+	if mva.ParentFolder != nil {
+		d.Set("parent_folder_id", mva.ParentFolder.Value)
+	}
+	flattenResourceAllocation(d, "cpu", mva.Config.CpuAllocation.GetResourceAllocationInfo())
+	flattenResourceAllocation(d, "memory", mva.Config.MemoryAllocation.GetResourceAllocationInfo())
+
+	if mva.VAppConfig == nil {
+		return nil
+	}
+
+	if err := d.Set("entity", flattenVAppEntities(d, mva.VAppConfig.EntityConfig)); err != nil {
+		return fmt.Errorf("Invalid entity to set: %s", err)
+	}
+
+	var product []map[string]interface{}
+	if len(mva.VAppConfig.Product) > 0 {
+		p := mva.VAppConfig.Product[0]
+		product = append(product, map[string]interface{}{
+			"name":         p.Name,
+			"vendor":       p.Vendor,
+			"version":      p.Version,
+			"full_version": p.FullVersion,
+			"vendor_url":   p.VendorUrl,
+			"product_url":  p.ProductUrl,
+			"app_url":      p.AppUrl,
+		})
+	}
+	if err := d.Set("product", product); err != nil {
+		return fmt.Errorf("Invalid product to set: %s", err)
+	}
+
+	properties := make(map[string]interface{})
+	for _, p := range mva.VAppConfig.Property {
+		properties[p.Id] = p.Value
+	}
+	if err := d.Set("properties", properties); err != nil {
+		return fmt.Errorf("Invalid properties to set: %s", err)
+	}
+
+	return nil
+}
+
+func resourceVSphereVAppContainerUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	vapp := object.NewVirtualApp(client.Client, vAppReference(d.Id()))
+
+	var name string
+	if d.HasChange("name") {
+		name = d.Get("name").(string)
+	}
+	spec := resourcePoolConfigSpec(d)
+	if err := vapp.ResourcePool.UpdateConfig(context.TODO(), name, &spec); err != nil {
+		return fmt.Errorf("error updating vApp %s: %s", d.Id(), err)
+	}
+
+	if d.HasChange("entity") || d.HasChange("product") || d.HasChange("properties") {
+		if err := vAppContainerConfigure(client, vapp, d); err != nil {
+			return err
+		}
+	}
+
+	return resourceVSphereVAppContainerRead(d, meta)
+}
+
+func resourceVSphereVAppContainerDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	vapp := object.NewVirtualApp(client.Client, vAppReference(d.Id()))
+
+	task, err := vapp.Destroy(context.TODO())
+	if err != nil {
+		return fmt.Errorf("error destroying vApp %s: %s", d.Id(), err)
+	}
+	if err := task.Wait(context.TODO()); err != nil {
+		return fmt.Errorf("error destroying vApp %s: %s", d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceVSphereVAppContainerImport imports a vApp by its inventory path,
+// such as "/dc1/vm/app1".
+func resourceVSphereVAppContainerImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*govmomi.Client)
+
+	ref, err := object.NewSearchIndex(client.Client).FindByInventoryPath(context.TODO(), d.Id())
+	if err != nil {
+		return nil, err
+	}
+	vapp, ok := ref.(*object.VirtualApp)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a vApp", d.Id())
+	}
+
+	d.SetId(vapp.Reference().Value)
+	return []*schema.ResourceData{d}, nil
+}
+
+func vAppReference(id string) types.ManagedObjectReference {
+	return types.ManagedObjectReference{
+		Type:  "VirtualApp",
+		Value: id,
+	}
+}
+
+// vAppParentReference returns the reference of the parent of a vApp, which is
+// either a vApp or a plain resource pool. The type of the object is taken from
+// the resource pool list of its own parent, as a managed object ID alone does
+// not tell the two apart.
+func vAppParentReference(client *govmomi.Client, id string) (types.ManagedObjectReference, error) {
+	ref := resourcePoolReference(id)
+	collector := property.DefaultCollector(client.Client)
+
+	var mrp mo.ResourcePool
+	if err := collector.RetrieveOne(context.TODO(), ref, []string{"parent"}, &mrp); err != nil {
+		return ref, err
+	}
+	// The root resource pool of a cluster or host cannot be a vApp.
+	if mrp.Parent == nil || (mrp.Parent.Type != "ResourcePool" && mrp.Parent.Type != "VirtualApp") {
+		return ref, nil
+	}
+
+	var parent mo.ResourcePool
+	if err := collector.RetrieveOne(context.TODO(), *mrp.Parent, []string{"resourcePool"}, &parent); err != nil {
+		return ref, err
+	}
+	for _, child := range parent.ResourcePool {
+		if child.Value == id {
+			return child, nil
+		}
+	}
+	return ref, nil
+}
+
+func folderReferenceFromID(id string) types.ManagedObjectReference {
+	return types.ManagedObjectReference{
+		Type:  "Folder",
+		Value: id,
+	}
+}
+
+// vmFolderForEntity returns the root VM folder of the datacenter that the
+// given managed entity belongs to.
+func vmFolderForEntity(client *govmomi.Client, ref types.ManagedObjectReference) (types.ManagedObjectReference, error) {
+	ref, err := datacenterForEntity(client, ref)
+	if err != nil {
+		return types.ManagedObjectReference{}, err
+	}
+
+	collector := property.DefaultCollector(client.Client)
+	var mdc mo.Datacenter
+	if err := collector.RetrieveOne(context.TODO(), ref, []string{"vmFolder"}, &mdc); err != nil {
+		return types.ManagedObjectReference{}, err
+	}
+	return mdc.VmFolder, nil
+}
+
+// vAppContainerConfigure applies the entity, product and property settings of
+// a vApp.
+func vAppContainerConfigure(client *govmomi.Client, vapp *object.VirtualApp, d *schema.ResourceData) error {
+	var mva mo.VirtualApp
+	if err := vapp.Properties(context.TODO(), vapp.Reference(), []string{"vAppConfig"}, &mva); err != nil {
+		return fmt.Errorf("error reading vApp %s: %s", d.Id(), err)
+	}
+	if mva.VAppConfig == nil {
+		return fmt.Errorf("vApp %s has no vApp configuration", d.Id())
+	}
+
+	entities, err := expandVAppEntities(d, mva.VAppConfig.EntityConfig)
+	if err != nil {
+		return err
+	}
+
+	spec := types.VAppConfigSpec{
+		VmConfigSpec: types.VmConfigSpec{
+			Product:  expandVAppProduct(d, mva.VAppConfig.Product),
+			Property: expandVAppProperties(d, mva.VAppConfig.Property),
+		},
+		EntityConfig: entities,
+	}
+	if err := vapp.UpdateConfig(context.TODO(), spec); err != nil {
+		return fmt.Errorf("error configuring vApp %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+// expandVAppEntities builds the entity configuration of the children listed
+// in the entity argument. Every child of a vApp already has an entity
+// configuration, which supplies the type of the child.
+func expandVAppEntities(d *schema.ResourceData, current []types.VAppEntityConfigInfo) ([]types.VAppEntityConfigInfo, error) {
+	var entities []types.VAppEntityConfigInfo
+	for _, v := range d.Get("entity").([]interface{}) {
+		e := v.(map[string]interface{})
+		id := e["target_id"].(string)
+
+		var key *types.ManagedObjectReference
+		for _, c := range current {
+			if c.Key != nil && c.Key.Value == id {
+				key = c.Key
+				break
+			}
+		}
+		if key == nil {
+			return nil, fmt.Errorf("%s is not a child of vApp %s", id, d.Id())
+		}
+
+		entities = append(entities, types.VAppEntityConfigInfo{
+			Key:             key,
+			StartOrder:      int32(e["start_order"].(int)),
+			StartDelay:      int32(e["start_delay"].(int)),
+			StartAction:     e["start_action"].(string),
+			WaitingForGuest: types.NewBool(e["wait_for_guest"].(bool)),
+			StopDelay:       int32(e["stop_delay"].(int)),
+			StopAction:      e["stop_action"].(string),
+		})
+	}
+	return entities, nil
+}
+
+// flattenVAppEntities returns the entity configuration of the children listed
+// in the entity argument, in the same order, or of all children on import.
+func flattenVAppEntities(d *schema.ResourceData, current []types.VAppEntityConfigInfo) []map[string]interface{} {
+	byID := make(map[string]types.VAppEntityConfigInfo)
+	var ids []string
+	for _, c := range current {
+		if c.Key == nil {
+			continue
+		}
+		byID[c.Key.Value] = c
+		ids = append(ids, c.Key.Value)
+	}
+
+	if v, ok := d.GetOk("entity"); ok {
+		ids = nil
+		for _, e := range v.([]interface{}) {
+			ids = append(ids, e.(map[string]interface{})["target_id"].(string))
+		}
+	}
+
+	var entities []map[string]interface{}
+	for _, id := range ids {
+		c, ok := byID[id]
+		if !ok {
+			continue
+		}
+		var waitForGuest bool
+		if c.WaitingForGuest != nil {
+			waitForGuest = *c.WaitingForGuest
+		}
+		entities = append(entities, map[string]interface{}{
+			"target_id":      id,
+			"start_order":    c.StartOrder,
+			"start_delay":    c.StartDelay,
+			"start_action":   c.StartAction,
+			"wait_for_guest": waitForGuest,
+			"stop_delay":     c.StopDelay,
+			"stop_action":    c.StopAction,
+		})
+	}
+	return entities
+}
+
+// expandVAppProduct returns the change to the product information of a vApp.
+// Only the first product of a vApp is managed.
+func expandVAppProduct(d *schema.ResourceData, current []types.VAppProductInfo) []types.VAppProductSpec {
+	v := d.Get("product").([]interface{})
+	if len(v) == 0 || v[0] == nil {
+		if len(current) == 0 {
+			return nil
+		}
+		return []types.VAppProductSpec{
+			{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{
+					Operation: types.ArrayUpdateOperationRemove,
+					RemoveKey: current[0].Key,
+				},
+			},
+		}
+	}
+
+	p := v[0].(map[string]interface{})
+	info := &types.VAppProductInfo{
+		Name:        p["name"].(string),
+		Vendor:      p["vendor"].(string),
+		Version:     p["version"].(string),
+		FullVersion: p["full_version"].(string),
+		VendorUrl:   p["vendor_url"].(string),
+		ProductUrl:  p["product_url"].(string),
+		AppUrl:      p["app_url"].(string),
+	}
+	op := types.ArrayUpdateOperationAdd
+	if len(current) > 0 {
+		op = types.ArrayUpdateOperationEdit
+		info.Key = current[0].Key
+	}
+	return []types.VAppProductSpec{
+		{
+			ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: op},
+			Info:            info,
+		},
+	}
+}
+
+// expandVAppProperties returns the changes that bring the properties of a
+// vApp in line with the properties argument. New properties are user
+// configurable strings.
+func expandVAppProperties(d *schema.ResourceData, current []types.VAppPropertyInfo) []types.VAppPropertySpec {
+	keys := make(map[string]int32)
+	var next int32
+	for _, p := range current {
+		keys[p.Id] = p.Key
+		if p.Key >= next {
+			next = p.Key + 1
+		}
+	}
+
+	properties := d.Get("properties").(map[string]interface{})
+	var specs []types.VAppPropertySpec
+	for id, value := range properties {
+		info := &types.VAppPropertyInfo{
+			Id:    id,
+			Value: value.(string),
+		}
+		op := types.ArrayUpdateOperationEdit
+		if key, ok := keys[id]; ok {
+			info.Key = key
+		} else {
+			op = types.ArrayUpdateOperationAdd
+			info.Key = next
+			info.Type = "string"
+			info.UserConfigurable = types.NewBool(true)
+			next++
+		}
+		specs = append(specs, types.VAppPropertySpec{
+			ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: op},
+			Info:            info,
+		})
+	}
+
+	for id, key := range keys {
+		if _, ok := properties[id]; ok {
+			continue
+		}
+		specs = append(specs, types.VAppPropertySpec{
+			ArrayUpdateSpec: types.ArrayUpdateSpec{
+				Operation: types.ArrayUpdateOperationRemove,
+				RemoveKey: key,
+			},
+		})
+	}
+	return specs
+}
@@ -0,0 +1,240 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+func TestAccVSphereVAppContainer_basic(t *testing.T) {
+	parent := os.Getenv("VSPHERE_RESOURCE_POOL_ID")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccVSphereResourcePoolPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereVAppContainerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereVAppContainerConfig, parent),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereVAppContainerExists("vsphere_vapp_container.app"),
+					testAccCheckVSphereVAppContainerExists("vsphere_vapp_container.child"),
+					resource.TestCheckResourceAttrPair("vsphere_vapp_container.child", "parent_resource_pool_id", "vsphere_vapp_container.app", "id"),
+					resource.TestCheckResourceAttr("vsphere_vapp_container.app", "product.0.name", "terraform-test"),
+					resource.TestCheckResourceAttr("vsphere_vapp_container.app", "properties.environment", "test"),
+					resource.TestCheckResourceAttr("vsphere_vapp_container.app", "memory_reservation", "256"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereVAppContainerConfigUpdated, parent),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereVAppContainerExists("vsphere_vapp_container.app"),
+					resource.TestCheckResourceAttr("vsphere_vapp_container.app", "name", "terraform-test-vapp-renamed"),
+					resource.TestCheckResourceAttr("vsphere_vapp_container.app", "product.0.version", "2.0"),
+					resource.TestCheckResourceAttr("vsphere_vapp_container.app", "properties.%", "1"),
+					resource.TestCheckResourceAttr("vsphere_vapp_container.app", "properties.tier", "web"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccVSphereVAppContainer_importBasic(t *testing.T) {
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+	parent := os.Getenv("VSPHERE_RESOURCE_POOL_ID")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccVSphereResourcePoolPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereVAppContainerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereVAppContainerConfig, parent),
+			},
+			{
+				ResourceName:      "vsphere_vapp_container.app",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("/%s/vm/terraform-test-vapp", datacenter),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestExpandVAppProperties(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceVSphereVAppContainer().Schema, map[string]interface{}{
+		"properties": map[string]interface{}{
+			"environment": "prod",
+			"tier":        "web",
+		},
+	})
+	current := []types.VAppPropertyInfo{
+		{Key: 3, Id: "environment", Value: "test"},
+		{Key: 5, Id: "owner", Value: "ops"},
+	}
+
+	ops := make(map[string]types.VAppPropertySpec)
+	for _, spec := range expandVAppProperties(d, current) {
+		if spec.Info != nil {
+			ops[spec.Info.Id] = spec
+		} else {
+			ops[fmt.Sprintf("remove-%v", spec.RemoveKey)] = spec
+		}
+	}
+
+	if s, ok := ops["environment"]; !ok || s.Operation != types.ArrayUpdateOperationEdit || s.Info.Key != 3 || s.Info.Value != "prod" {
+		t.Fatalf("expected an edit of key 3, got %#v", s)
+	}
+	if s, ok := ops["tier"]; !ok || s.Operation != types.ArrayUpdateOperationAdd || s.Info.Key != 6 {
+		t.Fatalf("expected an add with key 6, got %#v", s)
+	}
+	if s, ok := ops["remove-5"]; !ok || s.Operation != types.ArrayUpdateOperationRemove {
+		t.Fatalf("expected a removal of key 5, got %#v", s)
+	}
+	if len(ops) != 3 {
+		t.Fatalf("expected 3 changes, got %d", len(ops))
+	}
+}
+
+func TestExpandVAppEntities(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceVSphereVAppContainer().Schema, map[string]interface{}{
+		"entity": []interface{}{
+			map[string]interface{}{
+				"target_id":   "vm-42",
+				"start_order": 2,
+				"stop_action": "guestShutdown",
+			},
+		},
+	})
+	current := []types.VAppEntityConfigInfo{
+		{Key: &types.ManagedObjectReference{Type: "VirtualMachine", Value: "vm-42"}},
+		{Key: &types.ManagedObjectReference{Type: "VirtualApp", Value: "resgroup-v43"}},
+	}
+
+	entities, err := expandVAppEntities(d, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entities) != 1 {
+		t.Fatalf("expected 1 entity, got %d", len(entities))
+	}
+	e := entities[0]
+	if e.Key.Type != "VirtualMachine" || e.StartOrder != 2 || e.StartAction != "powerOn" || e.StopAction != "guestShutdown" || e.StartDelay != 120 {
+		t.Fatalf("unexpected entity %#v", e)
+	}
+
+	if _, err := expandVAppEntities(d, current[1:]); err == nil {
+		t.Fatal("expected an error for a target that is not a child of the vApp")
+	}
+}
+
+func testAccCheckVSphereVAppContainerDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*govmomi.Client)
+	collector := property.DefaultCollector(client.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "vsphere_vapp_container" {
+			continue
+		}
+
+		var mva mo.VirtualApp
+		err := collector.RetrieveOne(context.TODO(), vAppReference(rs.Primary.ID), []string{"name"}, &mva)
+		if err == nil {
+			return fmt.Errorf("vApp %s still exists", rs.Primary.ID)
+		}
+		if !isManagedObjectNotFoundError(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckVSphereVAppContainerExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*govmomi.Client)
+		collector := property.DefaultCollector(client.Client)
+
+		var mva mo.VirtualApp
+		if err := collector.RetrieveOne(context.TODO(), vAppReference(rs.Primary.ID), []string{"name"}, &mva); err != nil {
+			return fmt.Errorf("error finding vApp %s: %s", rs.Primary.ID, err)
+		}
+		if mva.Name != rs.Primary.Attributes["name"] {
+			return fmt.Errorf("expected vApp name %s, got %s", rs.Primary.Attributes["name"], mva.Name)
+		}
+
+		return nil
+	}
+}
+
+const testAccCheckVSphereVAppContainerConfig = `
+resource "vsphere_vapp_container" "app" {
+  name                    = "terraform-test-vapp"
+  parent_resource_pool_id = "%s"
+
+  memory_reservation = 256
+
+  product {
+    name    = "terraform-test"
+    vendor  = "HashiCorp"
+    version = "1.0"
+  }
+
+  properties {
+    environment = "test"
+  }
+}
+
+resource "vsphere_vapp_container" "child" {
+  name                    = "terraform-test-vapp-child"
+  parent_resource_pool_id = "${vsphere_vapp_container.app.id}"
+}
+`
+
+const testAccCheckVSphereVAppContainerConfigUpdated = `
+resource "vsphere_vapp_container" "app" {
+  name                    = "terraform-test-vapp-renamed"
+  parent_resource_pool_id = "%s"
+
+  memory_reservation = 256
+
+  product {
+    name    = "terraform-test"
+    vendor  = "HashiCorp"
+    version = "2.0"
+  }
+
+  properties {
+    tier = "web"
+  }
+}
+
+resource "vsphere_vapp_container" "child" {
+  name                    = "terraform-test-vapp-child"
+  parent_resource_pool_id = "${vsphere_vapp_container.app.id}"
+}
+`
@@ -12,8 +12,12 @@ import (
 	"github.com/vmware/govmomi/vim25/types"
 	"golang.org/x/net/context"
 	"path"
+	"regexp"
 )
 
+// virtualDiskIDRegexp matches the import ID of a vsphere_virtual_disk resource.
+var virtualDiskIDRegexp = regexp.MustCompile(`^(?:(.*)/)?\[([^\]]+)\] (.+)$`)
+
 type virtualDisk struct {
 	size        int
 	vmdkPath    string
@@ -29,6 +33,9 @@ func resourceVSphereVirtualDisk() *schema.Resource {
 		Create: resourceVSphereVirtualDiskCreate,
 		Read:   resourceVSphereVirtualDiskRead,
 		Delete: resourceVSphereVirtualDiskDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereVirtualDiskImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			// Size in GB
@@ -300,6 +307,80 @@ func resourceVSphereVirtualDiskDelete(d *schema.ResourceData, meta interface{})
 	return nil
 }
 
+// resourceVSphereVirtualDiskImport imports a virtual disk by its datastore
+// path, such as "[datastore1] disks/disk.vmdk". The datastore path can be
+// prefixed with "<datacenter>/" to search a datacenter other than the default.
+func resourceVSphereVirtualDiskImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	m := virtualDiskIDRegexp.FindStringSubmatch(d.Id())
+	if m == nil {
+		return nil, fmt.Errorf("virtual disk ID must be in the format [<datacenter>/][<datastore>] <path>, got %s", d.Id())
+	}
+	client := meta.(*govmomi.Client)
+
+	dc, err := getDatacenter(client, m[1])
+	if err != nil {
+		return nil, fmt.Errorf("Error finding Datacenter: %s: %s", m[1], err)
+	}
+	finder := find.NewFinder(client.Client, true)
+	finder = finder.SetDatacenter(dc)
+
+	ds, err := finder.Datastore(context.TODO(), m[2])
+	if err != nil {
+		return nil, fmt.Errorf("Error finding Datastore: %s: %s", m[2], err)
+	}
+	b, err := ds.Browser(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	spec := types.HostDatastoreBrowserSearchSpec{
+		Query: []types.BaseFileQuery{&types.VmDiskFileQuery{Details: &types.VmDiskFileQueryFlags{
+			CapacityKb:     true,
+			DiskType:       true,
+			ControllerType: types.NewBool(true),
+			Thin:           types.NewBool(true),
+		}}},
+		MatchPattern: []string{path.Base(m[3])},
+	}
+	task, err := b.SearchDatastore(context.TODO(), ds.Path(path.Dir(m[3])), &spec)
+	if err != nil {
+		return nil, err
+	}
+	info, err := task.WaitForResult(context.TODO(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error searching for virtual disk %s: %s", d.Id(), err)
+	}
+	res := info.Result.(types.HostDatastoreBrowserSearchResults)
+	if len(res.File) != 1 {
+		return nil, fmt.Errorf("virtual disk %s not found", d.Id())
+	}
+	fileInfo, ok := res.File[0].(*types.VmDiskFileInfo)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a virtual disk", d.Id())
+	}
+
+	// The API does not report whether a thick disk was eagerly zeroed, so
+	// thick disks are imported with the default type.
+	diskType := "eagerZeroedThick"
+	if fileInfo.Thin != nil && *fileInfo.Thin {
+		diskType = "thin"
+	}
+	adapterType := "lsiLogic"
+	switch fileInfo.ControllerType {
+	case "VirtualIDEController":
+		adapterType = "ide"
+	case "VirtualBusLogicController":
+		adapterType = "busLogic"
+	}
+
+	d.Set("datacenter", m[1])
+	d.Set("datastore", m[2])
+	d.Set("vmdk_path", m[3])
+	d.Set("type", diskType)
+	d.Set("adapter_type", adapterType)
+	return []*schema.ResourceData{d}, nil
+}
+
 // createHardDisk creates a new Hard Disk.
 func createHardDisk(client *govmomi.Client, size int, diskPath string, diskType string, adapterType string, dc string) error {
 	var vDiskType string
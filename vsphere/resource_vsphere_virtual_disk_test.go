@@ -52,6 +52,31 @@ func TestAccVSphereVirtualDisk_basic(t *testing.T) {
 	})
 }
 
+func TestAccVSphereVirtualDisk_importBasic(t *testing.T) {
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+	datastore := os.Getenv("VSPHERE_DATASTORE")
+	initTypeOpt := fmt.Sprintf("    type = \"%s\"\n", "thin")
+
+	rString := acctest.RandString(5)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereVirtualDiskDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckVSphereVirtuaDiskConfig_basic(rString, initTypeOpt, "", datacenter, datastore),
+			},
+			{
+				ResourceName:      "vsphere_virtual_disk.foo",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s/[%s] tfTestDisk-%s.vmdk", datacenter, datastore, rString),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func testAccVSphereVirtualDiskExists(name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[name]
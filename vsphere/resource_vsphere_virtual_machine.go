@@ -1,22 +1,29 @@
 package vsphere
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/methods"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 	"golang.org/x/net/context"
 )
 
+// ErrVirtualMachineNotFound is returned when a virtual machine cannot be located
+// by its instance UUID.
+var ErrVirtualMachineNotFound = errors.New("virtual machine not found")
+
 var DefaultDNSSuffixes = []string{
 	"vsphere.local",
 }
@@ -35,9 +42,28 @@ var DiskControllerTypes = []string{
 	"ide",
 }
 
+var firmwareTypes = []string{
+	string(types.GuestOsDescriptorFirmwareTypeBios),
+	string(types.GuestOsDescriptorFirmwareTypeEfi),
+}
+
+var scsiControllerTypes = []string{
+	"lsilogic",
+	"lsilogic-sas",
+	"pvscsi",
+	"buslogic",
+}
+
+var migratePriorities = []string{
+	string(types.VirtualMachineMovePriorityLowPriority),
+	string(types.VirtualMachineMovePriorityDefaultPriority),
+	string(types.VirtualMachineMovePriorityHighPriority),
+}
+
 type networkInterface struct {
 	deviceName       string
 	label            string
+	networkID        string
 	ipv4Address      string
 	ipv4PrefixLength int
 	ipv4Gateway      string
@@ -72,8 +98,12 @@ type cdrom struct {
 	path      string
 }
 
-type memoryAllocation struct {
-	reservation int64
+// diskMove is a disk that stays in place in the configuration but moves to
+// another datastore.
+type diskMove struct {
+	key       int32
+	datastore string
+	boot      bool
 }
 
 type virtualMachine struct {
@@ -86,7 +116,9 @@ type virtualMachine struct {
 	datastore             string
 	vcpu                  int32
 	memoryMb              int64
-	memoryAllocation      memoryAllocation
+	numCoresPerSocket     int32
+	cpuAllocation         *ResourceAllocationInfo
+	memoryAllocation      *ResourceAllocationInfo
 	annotation            string
 	template              string
 	networkInterfaces     []networkInterface
@@ -98,9 +130,20 @@ type virtualMachine struct {
 	dnsServers            []string
 	hasBootableVmdk       bool
 	linkedClone           bool
+	templateSnapshotID    string
+	templateSnapshot      string
 	skipCustomization     bool
 	enableDiskUUID        bool
+	cpuHotAddEnabled      bool
+	cpuHotRemoveEnabled   bool
+	memoryHotAddEnabled   bool
+	guestID               string
+	firmware              string
+	efiSecureBootEnabled  bool
+	hardwareVersion       int
+	scsiType              string
 	moid                  string
+	instanceUUID          string
 	windowsOptionalConfig windowsOptConfig
 	customConfigurations  map[string](types.AnyType)
 }
@@ -118,20 +161,22 @@ func vmPath(folder string, name string) string {
 }
 
 func resourceVSphereVirtualMachine() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceVSphereVirtualMachineCreate,
 		Read:   resourceVSphereVirtualMachineRead,
 		Update: resourceVSphereVirtualMachineUpdate,
 		Delete: resourceVSphereVirtualMachineDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereVirtualMachineImport,
+		},
 
-		SchemaVersion: 1,
+		SchemaVersion: 2,
 		MigrateState:  resourceVSphereVirtualMachineMigrateState,
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 			},
 
 			"hostname": &schema.Schema{
@@ -143,7 +188,9 @@ func resourceVSphereVirtualMachine() *schema.Resource {
 			"folder": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return strings.Trim(old, "/") == strings.Trim(new, "/")
+				},
 			},
 
 			"vcpu": &schema.Schema{
@@ -156,11 +203,28 @@ func resourceVSphereVirtualMachine() *schema.Resource {
 				Required: true,
 			},
 
-			"memory_reservation": &schema.Schema{
+			"cpu_hot_add_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"cpu_hot_remove_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"memory_hot_add_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"num_cores_per_socket": &schema.Schema{
 				Type:     schema.TypeInt,
 				Optional: true,
-				Default:  0,
-				ForceNew: true,
+				Default:  1,
 			},
 
 			"annotation": &schema.Schema{
@@ -168,22 +232,81 @@ func resourceVSphereVirtualMachine() *schema.Resource {
 				Optional: true,
 			},
 
+			"guest_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"firmware": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateStringInSlice(firmwareTypes),
+			},
+
+			"efi_secure_boot_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"hardware_version": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+
+			"scsi_type": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateStringInSlice(scsiControllerTypes),
+			},
+
 			"datacenter": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 			},
 
 			"cluster": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 			},
 
 			"resource_pool": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
+			},
+
+			"host": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"migrate_timeout": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  30,
+			},
+
+			"migrate_priority": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      string(types.VirtualMachineMovePriorityDefaultPriority),
+				ValidateFunc: validateStringInSlice(migratePriorities),
+			},
+
+			"shutdown_wait_timeout": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  3,
+			},
+
+			"force_power_off": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
 			},
 
 			"linked_clone": &schema.Schema{
@@ -192,6 +315,28 @@ func resourceVSphereVirtualMachine() *schema.Resource {
 				Default:  false,
 				ForceNew: true,
 			},
+
+			"template_snapshot_id": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"template_snapshot"},
+			},
+
+			"template_snapshot": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"template_snapshot_id"},
+			},
+
+			"revert_to_snapshot": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"custom_attributes": customAttributesSchema(),
+
 			"gateway": &schema.Schema{
 				Type:       schema.TypeString,
 				Optional:   true,
@@ -309,7 +454,15 @@ func resourceVSphereVirtualMachine() *schema.Resource {
 
 						"label": &schema.Schema{
 							Type:     schema.TypeString,
-							Required: true,
+							Optional: true,
+							Computed: true,
+							ForceNew: true,
+						},
+
+						"network_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
 							ForceNew: true,
 						},
 
@@ -500,6 +653,17 @@ func resourceVSphereVirtualMachine() *schema.Resource {
 			},
 		},
 	}
+
+	// Virtual machines have no expandable reservations.
+	for _, prefix := range []string{"cpu", "memory"} {
+		for k, v := range resourceAllocationSchema(prefix) {
+			if k != prefix+"_expandable" {
+				r.Schema[k] = v
+			}
+		}
+	}
+
+	return r
 }
 
 func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{}) error {
@@ -511,23 +675,98 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 	// make config spec
 	configSpec := types.VirtualMachineConfigSpec{}
 
-	if d.HasChange("vcpu") {
-		configSpec.NumCPUs = int32(d.Get("vcpu").(int))
+	// Hot add and hot remove settings can only be changed while the virtual
+	// machine is powered off, and only apply to later changes.
+	if d.HasChange("cpu_hot_add_enabled") {
+		configSpec.CpuHotAddEnabled = types.NewBool(d.Get("cpu_hot_add_enabled").(bool))
 		hasChanges = true
 		rebootRequired = true
 	}
 
-	if d.HasChange("memory") {
-		configSpec.MemoryMB = int64(d.Get("memory").(int))
+	if d.HasChange("cpu_hot_remove_enabled") {
+		configSpec.CpuHotRemoveEnabled = types.NewBool(d.Get("cpu_hot_remove_enabled").(bool))
+		hasChanges = true
+		rebootRequired = true
+	}
+
+	if d.HasChange("memory_hot_add_enabled") {
+		configSpec.MemoryHotAddEnabled = types.NewBool(d.Get("memory_hot_add_enabled").(bool))
+		hasChanges = true
+		rebootRequired = true
+	}
+
+	// The number of cores per socket can only be changed while the virtual
+	// machine is powered off.
+	if d.HasChange("num_cores_per_socket") {
+		configSpec.NumCoresPerSocket = int32(d.Get("num_cores_per_socket").(int))
 		hasChanges = true
 		rebootRequired = true
 	}
 
+	if resourceAllocationHasChange(d, "cpu") {
+		configSpec.CpuAllocation = virtualMachineResourceAllocation(d, "cpu")
+		hasChanges = true
+	}
+
+	if resourceAllocationHasChange(d, "memory") {
+		configSpec.MemoryAllocation = virtualMachineResourceAllocation(d, "memory")
+		hasChanges = true
+	}
+
+	if d.HasChange("vcpu") {
+		o, n := d.GetChange("vcpu")
+		configSpec.NumCPUs = int32(n.(int))
+		hasChanges = true
+		if !cpuChangeAllowedLive(d, o.(int), n.(int)) {
+			rebootRequired = true
+		}
+	}
+
+	if d.HasChange("memory") {
+		o, n := d.GetChange("memory")
+		configSpec.MemoryMB = int64(n.(int))
+		hasChanges = true
+		if !memoryChangeAllowedLive(d, o.(int), n.(int)) {
+			rebootRequired = true
+		}
+	}
+
 	if d.HasChange("annotation") {
 		configSpec.Annotation = d.Get("annotation").(string)
 		hasChanges = true
 	}
 
+	// The guest operating system, firmware and hardware version can only be
+	// changed while the virtual machine is powered off.
+	if d.HasChange("guest_id") {
+		configSpec.GuestId = d.Get("guest_id").(string)
+		hasChanges = true
+		rebootRequired = true
+	}
+
+	if d.HasChange("firmware") {
+		configSpec.Firmware = d.Get("firmware").(string)
+		hasChanges = true
+		rebootRequired = true
+	}
+
+	if d.HasChange("efi_secure_boot_enabled") {
+		configSpec.BootOptions = &types.VirtualMachineBootOptions{
+			EfiSecureBootEnabled: types.NewBool(d.Get("efi_secure_boot_enabled").(bool)),
+		}
+		hasChanges = true
+		rebootRequired = true
+	}
+
+	if d.HasChange("hardware_version") {
+		o, n := d.GetChange("hardware_version")
+		if n.(int) < o.(int) {
+			return fmt.Errorf("cannot downgrade virtual machine %s from hardware version %d to %d", d.Id(), o.(int), n.(int))
+		}
+		hasChanges = true
+		rebootRequired = true
+	}
+
 	client := meta.(*govmomi.Client)
 	dc, err := getDatacenter(client, d.Get("datacenter").(string))
 	if err != nil {
@@ -536,22 +775,82 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 	finder := find.NewFinder(client.Client, true)
 	finder = finder.SetDatacenter(dc)
 
-	vm, err := finder.VirtualMachine(context.TODO(), vmPath(d.Get("folder").(string), d.Get("name").(string)))
+	vm, err := virtualMachineFromUUID(client, d.Id())
 	if err != nil {
 		return err
 	}
 
-	if d.HasChange("disk") {
+	if err := applyCustomAttributes(client, vm.Reference(), d); err != nil {
+		return err
+	}
+
+	if d.HasChange("name") {
+		task, err := vm.Rename(context.TODO(), d.Get("name").(string))
+		if err != nil {
+			return fmt.Errorf("error renaming virtual machine %s: %s", d.Id(), err)
+		}
+		if err := task.Wait(context.TODO()); err != nil {
+			return fmt.Errorf("error renaming virtual machine %s: %s", d.Id(), err)
+		}
+	}
+
+	if d.HasChange("scsi_type") {
+		devices, err := vm.Device(context.TODO())
+		if err != nil {
+			return err
+		}
+		changes, err := changeSCSIControllerType(devices, d.Get("scsi_type").(string))
+		if err != nil {
+			return fmt.Errorf("error changing SCSI controller type of virtual machine %s: %s", d.Id(), err)
+		}
+		configSpec.DeviceChange = append(configSpec.DeviceChange, changes...)
 		hasChanges = true
-		oldDisks, newDisks := d.GetChange("disk")
-		oldDiskSet := oldDisks.(*schema.Set)
-		newDiskSet := newDisks.(*schema.Set)
+		rebootRequired = true
+	}
+
+	// A datacenter change moves the virtual machine to its folder in the new
+	// datacenter as part of the relocation.
+	if d.HasChange("folder") && !d.HasChange("datacenter") {
+		folder, err := virtualMachineFolder(finder, dc, d.Get("folder").(string))
+		if err != nil {
+			return err
+		}
+		task, err := folder.MoveInto(context.TODO(), []types.ManagedObjectReference{vm.Reference()})
+		if err != nil {
+			return fmt.Errorf("error moving virtual machine %s: %s", d.Id(), err)
+		}
+		if err := task.Wait(context.TODO()); err != nil {
+			return fmt.Errorf("error moving virtual machine %s: %s", d.Id(), err)
+		}
+	}
 
-		addedDisks := newDiskSet.Difference(oldDiskSet)
-		removedDisks := oldDiskSet.Difference(newDiskSet)
+	if d.HasChange("revert_to_snapshot") {
+		if v, ok := d.GetOk("revert_to_snapshot"); ok {
+			log.Printf("[INFO] Reverting virtual machine %s to snapshot %s", d.Id(), v.(string))
+			task, err := vm.RevertToSnapshot(context.TODO(), v.(string), false)
+			if err != nil {
+				return fmt.Errorf("error reverting to snapshot %s: %s", v.(string), err)
+			}
+			if err := task.Wait(context.TODO()); err != nil {
+				return fmt.Errorf("error reverting to snapshot %s: %s", v.(string), err)
+			}
+		}
+	}
+
+	oldDisks, newDisks := d.GetChange("disk")
+	addedDisks, removedDisks, movedDisks := diskChanges(oldDisks.(*schema.Set), newDisks.(*schema.Set))
+
+	if d.HasChange("datacenter") || d.HasChange("cluster") || d.HasChange("resource_pool") || d.HasChange("host") || len(movedDisks) > 0 {
+		if err := relocateVirtualMachine(client, vm, d, movedDisks); err != nil {
+			return err
+		}
+	}
+
+	if len(addedDisks) > 0 || len(removedDisks) > 0 {
+		hasChanges = true
 
 		// Removed disks
-		for _, diskRaw := range removedDisks.List() {
+		for _, diskRaw := range removedDisks {
 			if disk, ok := diskRaw.(map[string]interface{}); ok {
 				devices, err := vm.Device(context.TODO())
 				if err != nil {
@@ -571,7 +870,7 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 			}
 		}
 		// Added disks
-		for _, diskRaw := range addedDisks.List() {
+		for _, diskRaw := range addedDisks {
 			if disk, ok := diskRaw.(map[string]interface{}); ok {
 
 				var datastore *object.Datastore
@@ -644,16 +943,25 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 	log.Printf("[DEBUG] virtual machine config spec: %v", configSpec)
 
 	if rebootRequired {
-		log.Printf("[INFO] Shutting down virtual machine: %s", d.Id())
-
-		task, err := vm.PowerOff(context.TODO())
+		state, err := vm.PowerState(context.TODO())
 		if err != nil {
 			return err
 		}
+		// A virtual machine that is already powered off is reconfigured and
+		// left powered off.
+		rebootRequired = state == types.VirtualMachinePowerStatePoweredOn
+	}
 
-		err = task.Wait(context.TODO())
-		if err != nil {
-			return err
+	if rebootRequired {
+		timeout := time.Duration(d.Get("shutdown_wait_timeout").(int)) * time.Minute
+		if err := shutdownVirtualMachine(vm, timeout, d.Get("force_power_off").(bool)); err != nil {
+			return fmt.Errorf("error shutting down virtual machine %s: %s", d.Id(), err)
+		}
+	}
+
+	if d.HasChange("hardware_version") {
+		if err := upgradeVirtualMachine(client, vm, d.Get("hardware_version").(int)); err != nil {
+			return fmt.Errorf("error upgrading virtual machine %s: %s", d.Id(), err)
 		}
 	}
 
@@ -661,12 +969,10 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 
 	task, err := vm.Reconfigure(context.TODO(), configSpec)
 	if err != nil {
-		log.Printf("[ERROR] %s", err)
+		return fmt.Errorf("error reconfiguring virtual machine %s: %s", d.Id(), err)
 	}
-
-	err = task.Wait(context.TODO())
-	if err != nil {
-		log.Printf("[ERROR] %s", err)
+	if err := task.Wait(context.TODO()); err != nil {
+		return fmt.Errorf("error reconfiguring virtual machine %s: %s", d.Id(), err)
 	}
 
 	if rebootRequired {
@@ -688,12 +994,12 @@ func resourceVSphereVirtualMachineCreate(d *schema.ResourceData, meta interface{
 	client := meta.(*govmomi.Client)
 
 	vm := virtualMachine{
-		name:     d.Get("name").(string),
-		vcpu:     int32(d.Get("vcpu").(int)),
-		memoryMb: int64(d.Get("memory").(int)),
-		memoryAllocation: memoryAllocation{
-			reservation: int64(d.Get("memory_reservation").(int)),
-		},
+		name:              d.Get("name").(string),
+		vcpu:              int32(d.Get("vcpu").(int)),
+		memoryMb:          int64(d.Get("memory").(int)),
+		numCoresPerSocket: int32(d.Get("num_cores_per_socket").(int)),
+		cpuAllocation:     virtualMachineResourceAllocation(d, "cpu"),
+		memoryAllocation:  virtualMachineResourceAllocation(d, "memory"),
 	}
 
 	if v, ok := d.GetOk("hostname"); ok {
@@ -734,6 +1040,14 @@ func resourceVSphereVirtualMachineCreate(d *schema.ResourceData, meta interface{
 		vm.linkedClone = v.(bool)
 	}
 
+	if v, ok := d.GetOk("template_snapshot_id"); ok {
+		vm.templateSnapshotID = v.(string)
+	}
+
+	if v, ok := d.GetOk("template_snapshot"); ok {
+		vm.templateSnapshot = v.(string)
+	}
+
 	if v, ok := d.GetOk("skip_customization"); ok {
 		vm.skipCustomization = v.(bool)
 	}
@@ -742,6 +1056,24 @@ func resourceVSphereVirtualMachineCreate(d *schema.ResourceData, meta interface{
 		vm.enableDiskUUID = v.(bool)
 	}
 
+	if v, ok := d.GetOk("guest_id"); ok {
+		vm.guestID = v.(string)
+	}
+
+	if v, ok := d.GetOk("firmware"); ok {
+		vm.firmware = v.(string)
+	}
+
+	if v, ok := d.GetOk("hardware_version"); ok {
+		vm.hardwareVersion = v.(int)
+	}
+
+	vm.efiSecureBootEnabled = d.Get("efi_secure_boot_enabled").(bool)
+	vm.scsiType = d.Get("scsi_type").(string)
+	vm.cpuHotAddEnabled = d.Get("cpu_hot_add_enabled").(bool)
+	vm.cpuHotRemoveEnabled = d.Get("cpu_hot_remove_enabled").(bool)
+	vm.memoryHotAddEnabled = d.Get("memory_hot_add_enabled").(bool)
+
 	if raw, ok := d.GetOk("dns_suffixes"); ok {
 		for _, v := range raw.([]interface{}) {
 			vm.dnsSuffixes = append(vm.dnsSuffixes, v.(string))
@@ -774,6 +1106,10 @@ func resourceVSphereVirtualMachineCreate(d *schema.ResourceData, meta interface{
 		for i, v := range vL.([]interface{}) {
 			network := v.(map[string]interface{})
 			networks[i].label = network["label"].(string)
+			networks[i].networkID = network["network_id"].(string)
+			if networks[i].label == "" && networks[i].networkID == "" {
+				return fmt.Errorf("One of label or network_id must be set for each network_interface.")
+			}
 			if v, ok := network["ip_address"].(string); ok && v != "" {
 				networks[i].ipv4Address = v
 			}
@@ -942,8 +1278,16 @@ func resourceVSphereVirtualMachineCreate(d *schema.ResourceData, meta interface{
 		return err
 	}
 
-	d.SetId(vm.Path())
-	log.Printf("[INFO] Created virtual machine: %s", d.Id())
+	d.SetId(vm.instanceUUID)
+	log.Printf("[INFO] Created virtual machine: %s (%s)", vm.Path(), d.Id())
+
+	newVM, err := virtualMachineFromUUID(client, d.Id())
+	if err != nil {
+		return err
+	}
+	if err := applyCustomAttributes(client, newVM.Reference(), d); err != nil {
+		return err
+	}
 
 	return resourceVSphereVirtualMachineRead(d, meta)
 }
@@ -955,13 +1299,15 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 	if err != nil {
 		return err
 	}
-	finder := find.NewFinder(client.Client, true)
-	finder = finder.SetDatacenter(dc)
 
-	vm, err := finder.VirtualMachine(context.TODO(), d.Id())
+	vm, err := virtualMachineFromUUID(client, d.Id())
 	if err != nil {
-		d.SetId("")
-		return nil
+		if err == ErrVirtualMachineNotFound {
+			log.Printf("[DEBUG] virtual machine %s not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
 	}
 
 	err = d.Set("moid", vm.Reference().Value)
@@ -1077,6 +1423,9 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 	networkInterfaces := make([]map[string]interface{}, 0)
 
 	deviceList := object.VirtualDeviceList(mvm.Config.Hardware.Device)
+	if scsiType := virtualMachineSCSIType(deviceList); scsiType != "" {
+		d.Set("scsi_type", scsiType)
+	}
 	deviceList = deviceList.SelectByType((*types.VirtualEthernetCard)(nil))
 	log.Printf("[DEBUG] Device list %+v", deviceList)
 	for _, device := range deviceList {
@@ -1086,6 +1435,11 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 		DeviceName, _ := getNetworkName(client, vm, nic)
 		log.Printf("[DEBUG] device name %s", DeviceName)
 		networkInterface["label"] = DeviceName
+		networkID, err := getNetworkID(client, vm, nic)
+		if err != nil {
+			return err
+		}
+		networkInterface["network_id"] = networkID
 		networkInterface["mac_address"] = nic.GetVirtualEthernetCard().MacAddress
 		networkInterface["key"] = virtualDevice.Key
 		log.Printf("[DEBUG] networkInterface %#v", networkInterface)
@@ -1190,25 +1544,38 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 
 	d.Set("datacenter", dc)
 	d.Set("memory", mvm.Summary.Config.MemorySizeMB)
-	d.Set("memory_reservation", mvm.Summary.Config.MemoryReservation)
+	d.Set("num_cores_per_socket", mvm.Config.Hardware.NumCoresPerSocket)
+	flattenVirtualMachineResourceAllocation(d, "cpu", mvm.Config.CpuAllocation)
+	flattenVirtualMachineResourceAllocation(d, "memory", mvm.Config.MemoryAllocation)
 	d.Set("cpu", mvm.Summary.Config.NumCpu)
 	d.Set("datastore", rootDatastore)
 	d.Set("uuid", mvm.Summary.Config.Uuid)
 	d.Set("annotation", mvm.Summary.Config.Annotation)
+	if err := setGuestAndHardware(d, mvm.Config); err != nil {
+		return err
+	}
+	setHotAddFlags(d, mvm.Config)
+	d.Set("name", mvm.Summary.Config.Name)
+
+	folder, inFolder, err := virtualMachineFolderPath(client, vm.Reference())
+	if err != nil {
+		return err
+	}
+	if inFolder {
+		d.Set("folder", folder)
+	}
+
+	if err := readCustomAttributes(client, vm.Reference(), d); err != nil {
+		return err
+	}
 
 	return nil
 }
 
 func resourceVSphereVirtualMachineDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*govmomi.Client)
-	dc, err := getDatacenter(client, d.Get("datacenter").(string))
-	if err != nil {
-		return err
-	}
-	finder := find.NewFinder(client.Client, true)
-	finder = finder.SetDatacenter(dc)
 
-	vm, err := finder.VirtualMachine(context.TODO(), vmPath(d.Get("folder").(string), d.Get("name").(string)))
+	vm, err := virtualMachineFromUUID(client, d.Id())
 	if err != nil {
 		return err
 	}
@@ -1301,33 +1668,175 @@ func resourceVSphereVirtualMachineDelete(d *schema.ResourceData, meta interface{
 	return nil
 }
 
-// addHardDisk adds a new Hard Disk to the VirtualMachine.
-func addHardDisk(vm *object.VirtualMachine, size, iops int64, diskType string, datastore *object.Datastore, diskPath string, controller_type string) error {
-	devices, err := vm.Device(context.TODO())
+// resourceVSphereVirtualMachineImport imports a virtual machine by its instance
+// UUID. The disk and cdrom blocks are rebuilt from the devices of the virtual
+// machine, with every disk described as an existing vmdk. Arguments that are
+// only used while cloning or customizing are set to their defaults.
+func resourceVSphereVirtualMachineImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*govmomi.Client)
+
+	vm, err := virtualMachineFromUUID(client, d.Id())
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("error finding virtual machine %s: %s", d.Id(), err)
 	}
-	log.Printf("[DEBUG] vm devices: %#v\n", devices)
 
-	var controller types.BaseVirtualController
-	switch controller_type {
-	case "scsi":
-		controller, err = devices.FindDiskController(controller_type)
-	case "scsi-lsi-parallel":
-		controller = devices.PickController(&types.VirtualLsiLogicController{})
-	case "scsi-buslogic":
-		controller = devices.PickController(&types.VirtualBusLogicController{})
-	case "scsi-paravirtual":
-		controller = devices.PickController(&types.ParaVirtualSCSIController{})
-	case "scsi-lsi-sas":
-		controller = devices.PickController(&types.VirtualLsiLogicSASController{})
-	case "ide":
-		controller, err = devices.FindDiskController(controller_type)
-	default:
-		return fmt.Errorf("[ERROR] Unsupported disk controller provided: %v", controller_type)
+	finder := find.NewFinder(client.Client, true)
+	e, err := finder.Element(context.TODO(), vm.Reference())
+	if err != nil {
+		return nil, fmt.Errorf("error finding inventory path of virtual machine %s: %s", d.Id(), err)
 	}
-
-	if err != nil || controller == nil {
+	dcPath, err := datacenterInventoryPath(client, vm.Reference())
+	if err != nil {
+		return nil, err
+	}
+	folder, name, err := splitDatacenterInventoryPath(dcPath, "vm", e.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var mvm mo.VirtualMachine
+	collector := property.DefaultCollector(client.Client)
+	if err := collector.RetrieveOne(context.TODO(), vm.Reference(), []string{"config", "guest"}, &mvm); err != nil {
+		return nil, err
+	}
+	if mvm.Config.Template {
+		return nil, fmt.Errorf("%s is a template and cannot be imported", e.Path)
+	}
+
+	d.Set("name", name)
+	d.Set("folder", folder)
+	d.Set("datacenter", strings.TrimPrefix(dcPath, "/"))
+	d.Set("vcpu", mvm.Config.Hardware.NumCPU)
+	d.Set("memory", mvm.Config.Hardware.MemoryMB)
+	d.Set("num_cores_per_socket", mvm.Config.Hardware.NumCoresPerSocket)
+	flattenVirtualMachineResourceAllocation(d, "cpu", mvm.Config.CpuAllocation)
+	flattenVirtualMachineResourceAllocation(d, "memory", mvm.Config.MemoryAllocation)
+	setHotAddFlags(d, mvm.Config)
+	if err := setGuestAndHardware(d, mvm.Config); err != nil {
+		return nil, err
+	}
+	if mvm.Config.Flags.DiskUuidEnabled != nil {
+		d.Set("enable_disk_uuid", *mvm.Config.Flags.DiskUuidEnabled)
+	}
+	if domain := guestDomain(mvm.Guest); domain != "" {
+		d.Set("domain", domain)
+	}
+	// vSphere does not report the time zone of the guest, so time_zone keeps
+	// its default.
+	d.Set("skip_customization", false)
+	d.Set("linked_clone", false)
+
+	devices := object.VirtualDeviceList(mvm.Config.Hardware.Device)
+	if scsiType := virtualMachineSCSIType(devices); scsiType != "" {
+		d.Set("scsi_type", scsiType)
+	}
+
+	disks := make([]map[string]interface{}, 0)
+	for _, device := range devices.SelectByType((*types.VirtualDisk)(nil)) {
+		vd := device.(*types.VirtualDisk)
+		backing, ok := vd.Backing.(*types.VirtualDiskFlatVer2BackingInfo)
+		if !ok {
+			return nil, fmt.Errorf("disk %s uses an unsupported backing type and cannot be imported", devices.Name(vd))
+		}
+		var dp object.DatastorePath
+		if !dp.FromString(backing.FileName) {
+			return nil, fmt.Errorf("[ERROR] Failed trying to parse disk path: %v", backing.FileName)
+		}
+
+		disk := map[string]interface{}{
+			"datastore":       dp.Datastore,
+			"vmdk":            dp.Path,
+			"type":            virtualDiskType(backing),
+			"controller_type": "scsi",
+		}
+		if _, ok := devices.FindByKey(vd.ControllerKey).(*types.VirtualIDEController); ok {
+			disk["controller_type"] = "ide"
+		}
+		if vd.StorageIOAllocation != nil && vd.StorageIOAllocation.Limit > 0 {
+			disk["iops"] = vd.StorageIOAllocation.Limit
+		}
+		disks = append(disks, disk)
+	}
+	if err := d.Set("disk", disks); err != nil {
+		return nil, fmt.Errorf("Invalid disks to set: %#v", disks)
+	}
+
+	cdroms := make([]map[string]interface{}, 0)
+	for _, device := range devices.SelectByType((*types.VirtualCdrom)(nil)) {
+		backing, ok := device.GetVirtualDevice().Backing.(*types.VirtualCdromIsoBackingInfo)
+		if !ok {
+			continue
+		}
+		var dp object.DatastorePath
+		if !dp.FromString(backing.FileName) {
+			return nil, fmt.Errorf("[ERROR] Failed trying to parse cdrom image path: %v", backing.FileName)
+		}
+		cdroms = append(cdroms, map[string]interface{}{
+			"datastore": dp.Datastore,
+			"path":      dp.Path,
+		})
+	}
+	if err := d.Set("cdrom", cdroms); err != nil {
+		return nil, fmt.Errorf("Invalid cdroms to set: %#v", cdroms)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// virtualDiskType returns the disk type, as used by the type argument of the
+// disk block, that matches the provisioning of a flat disk backing.
+func virtualDiskType(backing *types.VirtualDiskFlatVer2BackingInfo) string {
+	switch {
+	case backing.ThinProvisioned != nil && *backing.ThinProvisioned:
+		return "thin"
+	case backing.EagerlyScrub != nil && *backing.EagerlyScrub:
+		return "eager_zeroed"
+	default:
+		return "lazy"
+	}
+}
+
+// guestDomain returns the DNS domain reported by VMware Tools for the guest,
+// or an empty string if it is not known.
+func guestDomain(guest *types.GuestInfo) string {
+	if guest == nil {
+		return ""
+	}
+	for _, stack := range guest.IpStack {
+		if stack.DnsConfig != nil && stack.DnsConfig.DomainName != "" {
+			return stack.DnsConfig.DomainName
+		}
+	}
+	return ""
+}
+
+// addHardDisk adds a new Hard Disk to the VirtualMachine.
+func addHardDisk(vm *object.VirtualMachine, size, iops int64, diskType string, datastore *object.Datastore, diskPath string, controller_type string) error {
+	devices, err := vm.Device(context.TODO())
+	if err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] vm devices: %#v\n", devices)
+
+	var controller types.BaseVirtualController
+	switch controller_type {
+	case "scsi":
+		controller, err = devices.FindDiskController(controller_type)
+	case "scsi-lsi-parallel":
+		controller = devices.PickController(&types.VirtualLsiLogicController{})
+	case "scsi-buslogic":
+		controller = devices.PickController(&types.VirtualBusLogicController{})
+	case "scsi-paravirtual":
+		controller = devices.PickController(&types.ParaVirtualSCSIController{})
+	case "scsi-lsi-sas":
+		controller = devices.PickController(&types.VirtualLsiLogicSASController{})
+	case "ide":
+		controller, err = devices.FindDiskController(controller_type)
+	default:
+		return fmt.Errorf("[ERROR] Unsupported disk controller provided: %v", controller_type)
+	}
+
+	if err != nil || controller == nil {
 		// Check if max number of scsi controller are already used
 		diskControllers := getSCSIControllers(devices)
 		if len(diskControllers) >= 4 {
@@ -1542,12 +2051,7 @@ func addCdrom(client *govmomi.Client, vm *object.VirtualMachine, datacenter *obj
 }
 
 // buildNetworkDevice builds VirtualDeviceConfigSpec for Network Device.
-func buildNetworkDevice(f *find.Finder, label, adapterType string, macAddress string) (*types.VirtualDeviceConfigSpec, error) {
-	network, err := f.Network(context.TODO(), "*"+label)
-	if err != nil {
-		return nil, err
-	}
-
+func buildNetworkDevice(network object.NetworkReference, adapterType string, macAddress string) (*types.VirtualDeviceConfigSpec, error) {
 	backing, err := network.EthernetCardBackingInfo(context.TODO())
 	if err != nil {
 		return nil, err
@@ -1757,6 +2261,47 @@ func createCdroms(client *govmomi.Client, vm *object.VirtualMachine, datacenter
 	return nil
 }
 
+// virtualMachineResourcePool finds the resource pool to place a virtual
+// machine in: the given resource pool or vApp, the root resource pool of the
+// given cluster or the default resource pool. The vApp is returned when the
+// placement target is a vApp.
+func virtualMachineResourcePool(client *govmomi.Client, finder *find.Finder, cluster, resourcePool string) (*object.ResourcePool, *object.VirtualApp, error) {
+	if resourcePool == "" {
+		if cluster == "" {
+			rp, err := finder.DefaultResourcePool(context.TODO())
+			return rp, nil, err
+		}
+		rp, err := finder.ResourcePool(context.TODO(), "*"+cluster+"/Resources")
+		return rp, nil, err
+	}
+
+	rp, err := finder.ResourcePool(context.TODO(), resourcePool)
+	if err == nil {
+		return rp, nil, nil
+	}
+	if _, ok := err.(*find.NotFoundError); !ok {
+		return nil, nil, err
+	}
+	// Fall back to a vApp, which is looked up relative to the VM folder of the
+	// datacenter.
+	vapp, err := finder.VirtualApp(context.TODO(), resourcePool)
+	if err == nil {
+		return vapp.ResourcePool, vapp, nil
+	}
+	if _, ok := err.(*find.NotFoundError); !ok {
+		return nil, nil, err
+	}
+	// Fall back to the managed object ID of a vApp, such as the id of a
+	// vsphere_vapp_container resource.
+	ref := vAppReference(resourcePool)
+	var mva mo.VirtualApp
+	if err := property.DefaultCollector(client.Client).RetrieveOne(context.TODO(), ref, []string{"name"}, &mva); err != nil {
+		return nil, nil, fmt.Errorf("resource pool or vApp %s not found", resourcePool)
+	}
+	vapp = object.NewVirtualApp(client.Client, ref)
+	return vapp.ResourcePool, vapp, nil
+}
+
 func (vm *virtualMachine) setupVirtualMachine(c *govmomi.Client) error {
 	dc, err := getDatacenter(c, vm.datacenter)
 
@@ -1782,24 +2327,9 @@ func (vm *virtualMachine) setupVirtualMachine(c *govmomi.Client) error {
 		}
 	}
 
-	var resourcePool *object.ResourcePool
-	if vm.resourcePool == "" {
-		if vm.cluster == "" {
-			resourcePool, err = finder.DefaultResourcePool(context.TODO())
-			if err != nil {
-				return err
-			}
-		} else {
-			resourcePool, err = finder.ResourcePool(context.TODO(), "*"+vm.cluster+"/Resources")
-			if err != nil {
-				return err
-			}
-		}
-	} else {
-		resourcePool, err = finder.ResourcePool(context.TODO(), vm.resourcePool)
-		if err != nil {
-			return err
-		}
+	resourcePool, vapp, err := virtualMachineResourcePool(c, finder, vm.cluster, vm.resourcePool)
+	if err != nil {
+		return err
 	}
 	log.Printf("[DEBUG] resource pool: %#v", resourcePool)
 
@@ -1827,19 +2357,32 @@ func (vm *virtualMachine) setupVirtualMachine(c *govmomi.Client) error {
 	configSpec := types.VirtualMachineConfigSpec{
 		Name:              vm.name,
 		NumCPUs:           vm.vcpu,
-		NumCoresPerSocket: 1,
+		NumCoresPerSocket: vm.numCoresPerSocket,
 		MemoryMB:          vm.memoryMb,
-		MemoryAllocation: &types.ResourceAllocationInfo{
-			Reservation: vm.memoryAllocation.reservation,
-		},
+		CpuAllocation:     vm.cpuAllocation,
+		MemoryAllocation:  vm.memoryAllocation,
 		Flags: &types.VirtualMachineFlagInfo{
 			DiskUuidEnabled: &vm.enableDiskUUID,
 		},
-		Annotation: vm.annotation,
+		CpuHotAddEnabled:    &vm.cpuHotAddEnabled,
+		CpuHotRemoveEnabled: &vm.cpuHotRemoveEnabled,
+		MemoryHotAddEnabled: &vm.memoryHotAddEnabled,
+		Annotation:          vm.annotation,
 	}
 	if vm.template == "" {
 		configSpec.GuestId = "otherLinux64Guest"
 	}
+	if vm.guestID != "" {
+		configSpec.GuestId = vm.guestID
+	}
+	if vm.firmware != "" {
+		configSpec.Firmware = vm.firmware
+	}
+	if vm.efiSecureBootEnabled {
+		configSpec.BootOptions = &types.VirtualMachineBootOptions{
+			EfiSecureBootEnabled: &vm.efiSecureBootEnabled,
+		}
+	}
 	log.Printf("[DEBUG] virtual machine config spec: %v", configSpec)
 
 	// make ExtraConfig
@@ -1910,7 +2453,16 @@ func (vm *virtualMachine) setupVirtualMachine(c *govmomi.Client) error {
 		} else {
 			networkDeviceType = "vmxnet3"
 		}
-		nd, err := buildNetworkDevice(finder, network.label, networkDeviceType, network.macAddress)
+		var networkRef object.NetworkReference
+		if network.networkID != "" {
+			networkRef, err = networkFromID(c, network.networkID)
+		} else {
+			networkRef, err = finder.Network(context.TODO(), "*"+network.label)
+		}
+		if err != nil {
+			return err
+		}
+		nd, err := buildNetworkDevice(networkRef, networkDeviceType, network.macAddress)
 		if err != nil {
 			return err
 		}
@@ -1978,9 +2530,12 @@ func (vm *virtualMachine) setupVirtualMachine(c *govmomi.Client) error {
 			return err
 		}
 		log.Printf("[DEBUG] datastore: %#v", mds.Name)
-		scsi, err := object.SCSIControllerTypes().CreateSCSIController("scsi")
+		if vm.hardwareVersion != 0 {
+			configSpec.Version = virtualMachineHardwareVersion(vm.hardwareVersion)
+		}
+		scsi, err := object.SCSIControllerTypes().CreateSCSIController(vm.scsiType)
 		if err != nil {
-			log.Printf("[ERROR] %s", err)
+			return err
 		}
 
 		configSpec.DeviceChange = append(configSpec.DeviceChange, &types.VirtualDeviceConfigSpec{
@@ -1990,16 +2545,14 @@ func (vm *virtualMachine) setupVirtualMachine(c *govmomi.Client) error {
 
 		configSpec.Files = &types.VirtualMachineFileInfo{VmPathName: fmt.Sprintf("[%s]", mds.Name)}
 
-		task, err = folder.CreateVM(context.TODO(), configSpec, resourcePool, nil)
-		if err != nil {
-			log.Printf("[ERROR] %s", err)
+		if vapp != nil {
+			task, err = vapp.CreateChildVM(context.TODO(), configSpec, nil)
+		} else {
+			task, err = folder.CreateVM(context.TODO(), configSpec, resourcePool, nil)
 		}
-
-		err = task.Wait(context.TODO())
 		if err != nil {
-			log.Printf("[ERROR] %s", err)
+			return err
 		}
-
 	} else {
 
 		relocateSpec, err := buildVMRelocateSpec(resourcePool, datastore, template, vm.linkedClone, vm.hardDisks[0].initType)
@@ -2016,7 +2569,17 @@ func (vm *virtualMachine) setupVirtualMachine(c *govmomi.Client) error {
 			Config:   &configSpec,
 			PowerOn:  false,
 		}
-		if vm.linkedClone {
+		if vm.templateSnapshotID != "" {
+			ref := virtualMachineSnapshotReference(vm.templateSnapshotID)
+			cloneSpec.Snapshot = &ref
+		} else if vm.templateSnapshot != "" {
+			snapshot, err := template.FindSnapshot(context.TODO(), vm.templateSnapshot)
+			if err != nil {
+				return fmt.Errorf("error finding template snapshot %s: %s", vm.templateSnapshot, err)
+			}
+			ref := snapshot.Reference()
+			cloneSpec.Snapshot = &ref
+		} else if vm.linkedClone {
 			if template_mo.Snapshot == nil {
 				return fmt.Errorf("`linkedClone=true`, but image VM has no snapshots")
 			}
@@ -2030,15 +2593,13 @@ func (vm *virtualMachine) setupVirtualMachine(c *govmomi.Client) error {
 		}
 	}
 
-	err = task.Wait(context.TODO())
-	if err != nil {
-		log.Printf("[ERROR] %s", err)
-	}
-
-	newVM, err := finder.VirtualMachine(context.TODO(), vm.Path())
+	// Use the result of the task, as virtual machines in a vApp are not found
+	// under their folder path.
+	info, err := task.WaitForResult(context.TODO(), nil)
 	if err != nil {
 		return err
 	}
+	newVM := object.NewVirtualMachine(c.Client, info.Result.(types.ManagedObjectReference))
 	log.Printf("[DEBUG] new vm: %v", newVM)
 
 	devices, err := newVM.Device(context.TODO())
@@ -2047,6 +2608,22 @@ func (vm *virtualMachine) setupVirtualMachine(c *govmomi.Client) error {
 		return err
 	}
 
+	// A clone keeps the SCSI controller of its template, so replace it when a
+	// different type is configured.
+	if vm.template != "" && vm.scsiType != "" && virtualMachineSCSIType(devices) != vm.scsiType {
+		changes, err := changeSCSIControllerType(devices, vm.scsiType)
+		if err != nil {
+			return fmt.Errorf("error changing SCSI controller type: %s", err)
+		}
+		task, err := newVM.Reconfigure(context.TODO(), types.VirtualMachineConfigSpec{DeviceChange: changes})
+		if err != nil {
+			return fmt.Errorf("error changing SCSI controller type: %s", err)
+		}
+		if err := task.Wait(context.TODO()); err != nil {
+			return fmt.Errorf("error changing SCSI controller type: %s", err)
+		}
+	}
+
 	for _, dvc := range devices {
 		// Issue 3559/3560: Delete all ethernet devices to add the correct ones later
 		if devices.Type(dvc) == "ethernet" {
@@ -2070,7 +2647,25 @@ func (vm *virtualMachine) setupVirtualMachine(c *govmomi.Client) error {
 		return err
 	}
 
-	newVM.Properties(context.TODO(), newVM.Reference(), []string{"summary", "config"}, &vm_mo)
+	err = newVM.Properties(context.TODO(), newVM.Reference(), []string{"summary", "config"}, &vm_mo)
+	if err != nil {
+		return err
+	}
+	vm.instanceUUID = vm_mo.Config.InstanceUuid
+
+	// A clone keeps the hardware version of its template, so upgrade it before
+	// it is powered on.
+	if vm.template != "" && vm.hardwareVersion != 0 {
+		upgrade, err := hardwareVersionUpgradeRequired(vm_mo.Config.Version, vm.hardwareVersion)
+		if err != nil {
+			return err
+		}
+		if upgrade {
+			if err := upgradeVirtualMachine(c, newVM, vm.hardwareVersion); err != nil {
+				return fmt.Errorf("error upgrading virtual machine: %s", err)
+			}
+		}
+	}
 	firstDisk := 0
 	if vm.template != "" {
 		firstDisk++
@@ -2214,6 +2809,410 @@ func (vm *virtualMachine) setupVirtualMachine(c *govmomi.Client) error {
 	}
 	return nil
 }
+
+// virtualMachineFromUUID locates a virtual machine by its instance UUID. The
+// search spans all datacenters, so the virtual machine is found regardless of
+// its name or location in the inventory.
+func virtualMachineFromUUID(client *govmomi.Client, uuid string) (*object.VirtualMachine, error) {
+	si := object.NewSearchIndex(client.Client)
+	ref, err := si.FindByUuid(context.TODO(), nil, uuid, true, types.NewBool(true))
+	if err != nil {
+		return nil, fmt.Errorf("error searching for virtual machine %s: %s", uuid, err)
+	}
+	if ref == nil {
+		return nil, ErrVirtualMachineNotFound
+	}
+	vm, ok := ref.(*object.VirtualMachine)
+	if !ok {
+		return nil, fmt.Errorf("object with UUID %s is a %s, not a virtual machine", uuid, ref.Reference().Type)
+	}
+	return vm, nil
+}
+
+// virtualMachineResourceAllocation returns the CPU or memory allocation of a
+// virtual machine. It is expandResourceAllocation without the expandable
+// reservation, which virtual machines do not have.
+func virtualMachineResourceAllocation(d *schema.ResourceData, prefix string) *ResourceAllocationInfo {
+	return &ResourceAllocationInfo{
+		Reservation: int64(d.Get(prefix + "_reservation").(int)),
+		Limit:       int64(d.Get(prefix + "_limit").(int)),
+		Shares: &types.SharesInfo{
+			Level:  types.SharesLevel(d.Get(prefix + "_share_level").(string)),
+			Shares: int32(d.Get(prefix + "_shares").(int)),
+		},
+	}
+}
+
+// flattenVirtualMachineResourceAllocation sets the CPU or memory allocation
+// arguments of a virtual machine, which has no expandable reservation.
+func flattenVirtualMachineResourceAllocation(d *schema.ResourceData, prefix string, info types.BaseResourceAllocationInfo) {
+	if info == nil {
+		return
+	}
+	allocation := *info.GetResourceAllocationInfo()
+	allocation.ExpandableReservation = nil
+	flattenResourceAllocation(d, prefix, &allocation)
+}
+
+// resourceAllocationHasChange reports whether any CPU or memory allocation
+// argument of a virtual machine has changed.
+func resourceAllocationHasChange(d *schema.ResourceData, prefix string) bool {
+	for _, k := range []string{"_share_level", "_shares", "_reservation", "_limit"} {
+		if d.HasChange(prefix + k) {
+			return true
+		}
+	}
+	return false
+}
+
+// cpuChangeAllowedLive reports whether the vCPU count of a running virtual
+// machine can change from o to n without powering it off.
+func cpuChangeAllowedLive(d *schema.ResourceData, o, n int) bool {
+	if d.HasChange("cpu_hot_add_enabled") || d.HasChange("cpu_hot_remove_enabled") {
+		return false
+	}
+	if n > o {
+		return d.Get("cpu_hot_add_enabled").(bool)
+	}
+	return d.Get("cpu_hot_remove_enabled").(bool)
+}
+
+// memoryChangeAllowedLive reports whether the memory of a running virtual
+// machine can change from o to n MB without powering it off. Memory can only
+// be added while the virtual machine is running.
+func memoryChangeAllowedLive(d *schema.ResourceData, o, n int) bool {
+	if d.HasChange("memory_hot_add_enabled") {
+		return false
+	}
+	return n > o && d.Get("memory_hot_add_enabled").(bool)
+}
+
+// setHotAddFlags sets the CPU and memory hot add and hot remove settings from
+// the configuration of a virtual machine.
+func setHotAddFlags(d *schema.ResourceData, config *types.VirtualMachineConfigInfo) {
+	if config == nil {
+		return
+	}
+	if config.CpuHotAddEnabled != nil {
+		d.Set("cpu_hot_add_enabled", *config.CpuHotAddEnabled)
+	}
+	if config.CpuHotRemoveEnabled != nil {
+		d.Set("cpu_hot_remove_enabled", *config.CpuHotRemoveEnabled)
+	}
+	if config.MemoryHotAddEnabled != nil {
+		d.Set("memory_hot_add_enabled", *config.MemoryHotAddEnabled)
+	}
+}
+
+// virtualMachineHardwareVersion returns the virtual hardware version string
+// for a hardware version number, such as vmx-13 for 13.
+func virtualMachineHardwareVersion(version int) string {
+	return fmt.Sprintf("vmx-%02d", version)
+}
+
+// setGuestAndHardware sets the guest operating system, firmware and hardware
+// version from the configuration of a virtual machine.
+func setGuestAndHardware(d *schema.ResourceData, config *types.VirtualMachineConfigInfo) error {
+	if config == nil {
+		return nil
+	}
+	d.Set("guest_id", config.GuestId)
+	d.Set("firmware", config.Firmware)
+	if config.BootOptions != nil && config.BootOptions.EfiSecureBootEnabled != nil {
+		d.Set("efi_secure_boot_enabled", *config.BootOptions.EfiSecureBootEnabled)
+	}
+	if config.Version == "" {
+		return nil
+	}
+	version, err := parseVirtualMachineHardwareVersion(config.Version)
+	if err != nil {
+		return err
+	}
+	d.Set("hardware_version", version)
+	return nil
+}
+
+// parseVirtualMachineHardwareVersion returns the hardware version number of a
+// virtual hardware version string, such as 13 for vmx-13.
+func parseVirtualMachineHardwareVersion(s string) (int, error) {
+	var version int
+	if _, err := fmt.Sscanf(s, "vmx-%d", &version); err != nil {
+		return 0, fmt.Errorf("error parsing hardware version %q: %s", s, err)
+	}
+	return version, nil
+}
+
+// hardwareVersionUpgradeRequired reports whether a virtual machine with the
+// virtual hardware version current must be upgraded to reach version. The
+// virtual hardware cannot be downgraded.
+func hardwareVersionUpgradeRequired(current string, version int) (bool, error) {
+	n, err := parseVirtualMachineHardwareVersion(current)
+	if err != nil {
+		return false, err
+	}
+	if version < n {
+		return false, fmt.Errorf("cannot downgrade from hardware version %d to %d", n, version)
+	}
+	return version > n, nil
+}
+
+// virtualMachineSCSIType returns the type of the first SCSI controller of a
+// virtual machine, such as lsilogic, or an empty string if it has none.
+func virtualMachineSCSIType(devices object.VirtualDeviceList) string {
+	for _, device := range devices.SelectByType((*types.VirtualSCSIController)(nil)) {
+		return devices.Type(device)
+	}
+	return ""
+}
+
+// changeSCSIControllerType returns the device changes that replace the first
+// SCSI controller of a virtual machine with a controller of the given type on
+// the same bus, and move the disks of the old controller to the new one.
+func changeSCSIControllerType(devices object.VirtualDeviceList, scsiType string) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	controllers := devices.SelectByType((*types.VirtualSCSIController)(nil))
+	if len(controllers) == 0 {
+		return nil, errors.New("no SCSI controller found")
+	}
+	old := controllers[0].(types.BaseVirtualSCSIController).GetVirtualSCSIController()
+
+	device, err := devices.CreateSCSIController(scsiType)
+	if err != nil {
+		return nil, err
+	}
+	scsi := device.(types.BaseVirtualSCSIController).GetVirtualSCSIController()
+	scsi.BusNumber = old.BusNumber
+	scsi.SharedBus = old.SharedBus
+
+	changes := []types.BaseVirtualDeviceConfigSpec{
+		&types.VirtualDeviceConfigSpec{
+			Operation: types.VirtualDeviceConfigSpecOperationRemove,
+			Device:    controllers[0],
+		},
+		&types.VirtualDeviceConfigSpec{
+			Operation: types.VirtualDeviceConfigSpecOperationAdd,
+			Device:    device,
+		},
+	}
+	for _, disk := range devices.SelectByType((*types.VirtualDisk)(nil)) {
+		vd := disk.GetVirtualDevice()
+		if vd.ControllerKey != old.Key {
+			continue
+		}
+		vd.ControllerKey = scsi.Key
+		changes = append(changes, &types.VirtualDeviceConfigSpec{
+			Operation: types.VirtualDeviceConfigSpecOperationEdit,
+			Device:    disk,
+		})
+	}
+	return changes, nil
+}
+
+// upgradeVirtualMachine upgrades the virtual hardware of a powered off
+// virtual machine to the given version.
+func upgradeVirtualMachine(client *govmomi.Client, vm *object.VirtualMachine, version int) error {
+	log.Printf("[INFO] Upgrading virtual machine %s to hardware version %d", vm.Reference().Value, version)
+
+	req := &types.UpgradeVM_Task{
+		This:    vm.Reference(),
+		Version: virtualMachineHardwareVersion(version),
+	}
+	res, err := methods.UpgradeVM_Task(context.TODO(), client, req)
+	if err != nil {
+		return err
+	}
+	return object.NewTask(client.Client, res.Returnval).Wait(context.TODO())
+}
+
+// shutdownVirtualMachine shuts down the guest operating system through VMware
+// Tools and waits up to timeout for the virtual machine to power off. When the
+// guest cannot be shut down in time, the virtual machine is powered off if
+// force is set, and an error is returned otherwise.
+func shutdownVirtualMachine(vm *object.VirtualMachine, timeout time.Duration, force bool) error {
+	log.Printf("[INFO] Shutting down virtual machine: %s", vm.Reference().Value)
+
+	err := vm.ShutdownGuest(context.TODO())
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		err = vm.WaitForPowerState(ctx, types.VirtualMachinePowerStatePoweredOff)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out after %s waiting for guest shutdown", timeout)
+		}
+	}
+	if !force {
+		return err
+	}
+
+	log.Printf("[INFO] Guest shutdown of virtual machine %s failed (%s), powering off", vm.Reference().Value, err)
+	task, err := vm.PowerOff(context.TODO())
+	if err != nil {
+		return err
+	}
+	return task.Wait(context.TODO())
+}
+
+// virtualMachineFolder finds a VM folder by its path relative to the VM
+// folder of the datacenter. An empty path is the VM folder itself.
+func virtualMachineFolder(finder *find.Finder, dc *object.Datacenter, folder string) (*object.Folder, error) {
+	if folder == "" {
+		dcFolders, err := dc.Folders(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+		return dcFolders.VmFolder, nil
+	}
+	return finder.Folder(context.TODO(), fmt.Sprintf("%s/vm/%s", dc.InventoryPath, strings.Trim(folder, "/")))
+}
+
+// virtualMachineFolderPath returns the path of the folder of a virtual
+// machine relative to the VM folder of its datacenter. The second result is
+// false for virtual machines that are not in a folder, such as those in a
+// vApp.
+func virtualMachineFolderPath(client *govmomi.Client, ref types.ManagedObjectReference) (string, bool, error) {
+	collector := property.DefaultCollector(client.Client)
+
+	var me mo.ManagedEntity
+	if err := collector.RetrieveOne(context.TODO(), ref, []string{"parent"}, &me); err != nil {
+		return "", false, err
+	}
+
+	var names []string
+	for parent := me.Parent; parent != nil && parent.Type == "Folder"; {
+		var f mo.Folder
+		if err := collector.RetrieveOne(context.TODO(), *parent, []string{"name", "parent"}, &f); err != nil {
+			return "", false, err
+		}
+		if f.Parent != nil && f.Parent.Type == "Datacenter" {
+			// The VM folder of the datacenter.
+			for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+				names[i], names[j] = names[j], names[i]
+			}
+			return strings.Join(names, "/"), true, nil
+		}
+		names = append(names, f.Name)
+		parent = f.Parent
+	}
+	return "", false, nil
+}
+
+// diskChanges returns the disks added to and removed from the disk set, and
+// the disks whose only change is their datastore, which are migrated instead.
+func diskChanges(o, n *schema.Set) ([]interface{}, []interface{}, []diskMove) {
+	added := n.Difference(o).List()
+	var removed []interface{}
+	var moved []diskMove
+
+	for _, r := range o.Difference(n).List() {
+		oldDisk := r.(map[string]interface{})
+		match := -1
+		for i, a := range added {
+			if sameDiskExceptDatastore(oldDisk, a.(map[string]interface{})) {
+				match = i
+				break
+			}
+		}
+		if match < 0 || oldDisk["key"].(int) == 0 {
+			removed = append(removed, r)
+			continue
+		}
+
+		newDisk := added[match].(map[string]interface{})
+		moved = append(moved, diskMove{
+			key:       int32(oldDisk["key"].(int)),
+			datastore: newDisk["datastore"].(string),
+			boot:      oldDisk["template"].(string) != "" || oldDisk["bootable"].(bool),
+		})
+		added = append(added[:match], added[match+1:]...)
+	}
+
+	return added, removed, moved
+}
+
+// sameDiskExceptDatastore returns true if two disks differ in their datastore
+// only.
+func sameDiskExceptDatastore(a, b map[string]interface{}) bool {
+	if a["datastore"] == b["datastore"] {
+		return false
+	}
+	for _, k := range []string{"template", "type", "size", "name", "iops", "vmdk", "bootable", "keep_on_remove", "controller_type"} {
+		if a[k] != b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// relocateVirtualMachine migrates a virtual machine to the datacenter,
+// cluster, resource pool and host in the configuration, and moves the given
+// disks to their new datastores. The home files of the virtual machine move
+// with its boot disk.
+func relocateVirtualMachine(client *govmomi.Client, vm *object.VirtualMachine, d *schema.ResourceData, moved []diskMove) error {
+	dc, err := getDatacenter(client, d.Get("datacenter").(string))
+	if err != nil {
+		return err
+	}
+	finder := find.NewFinder(client.Client, true)
+	finder = finder.SetDatacenter(dc)
+
+	pool, _, err := virtualMachineResourcePool(client, finder, d.Get("cluster").(string), d.Get("resource_pool").(string))
+	if err != nil {
+		return err
+	}
+	poolRef := pool.Reference()
+	spec := types.VirtualMachineRelocateSpec{
+		Pool: &poolRef,
+	}
+
+	if v, ok := d.GetOk("host"); ok {
+		host, err := finder.HostSystem(context.TODO(), v.(string))
+		if err != nil {
+			return err
+		}
+		hostRef := host.Reference()
+		spec.Host = &hostRef
+	}
+
+	if d.HasChange("datacenter") {
+		folder, err := virtualMachineFolder(finder, dc, d.Get("folder").(string))
+		if err != nil {
+			return err
+		}
+		folderRef := folder.Reference()
+		spec.Folder = &folderRef
+	}
+
+	for _, m := range moved {
+		ds, err := finder.Datastore(context.TODO(), m.datastore)
+		if err != nil {
+			return err
+		}
+		dsRef := ds.Reference()
+		spec.Disk = append(spec.Disk, types.VirtualMachineRelocateSpecDiskLocator{
+			DiskId:    m.key,
+			Datastore: dsRef,
+		})
+		if m.boot {
+			spec.Datastore = &dsRef
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(d.Get("migrate_timeout").(int))*time.Minute)
+	defer cancel()
+
+	log.Printf("[INFO] Relocating virtual machine: %s", d.Id())
+	task, err := vm.Relocate(ctx, spec, types.VirtualMachineMovePriority(d.Get("migrate_priority").(string)))
+	if err != nil {
+		return fmt.Errorf("error relocating virtual machine %s: %s", d.Id(), err)
+	}
+	if err := task.Wait(ctx); err != nil {
+		return fmt.Errorf("error relocating virtual machine %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
 func getNetworkName(c *govmomi.Client, vm *object.VirtualMachine, nic types.BaseVirtualEthernetCard) (string, error) {
 	backingInfo := nic.GetVirtualEthernetCard().Backing
 	var deviceName string
@@ -2240,6 +3239,41 @@ func getNetworkName(c *govmomi.Client, vm *object.VirtualMachine, nic types.Base
 	return deviceName, nil
 }
 
+// getNetworkID returns the managed object ID of the network that backs a
+// virtual network interface.
+func getNetworkID(c *govmomi.Client, vm *object.VirtualMachine, nic types.BaseVirtualEthernetCard) (string, error) {
+	switch backing := nic.GetVirtualEthernetCard().Backing.(type) {
+	case *types.VirtualEthernetCardNetworkBackingInfo:
+		if backing.Network != nil {
+			return backing.Network.Value, nil
+		}
+	case *types.VirtualEthernetCardDistributedVirtualPortBackingInfo:
+		return backing.Port.PortgroupKey, nil
+	case *types.VirtualEthernetCardOpaqueNetworkBackingInfo:
+		// Opaque network backings only carry the ID assigned by the network
+		// provider, so the matching network is looked up among the networks
+		// of the virtual machine.
+		var mvm mo.VirtualMachine
+		if err := vm.Properties(context.TODO(), vm.Reference(), []string{"network"}, &mvm); err != nil {
+			return "", err
+		}
+		collector := property.DefaultCollector(c.Client)
+		for _, ref := range mvm.Network {
+			if ref.Type != "OpaqueNetwork" {
+				continue
+			}
+			var mon mo.OpaqueNetwork
+			if err := collector.RetrieveOne(context.TODO(), ref, []string{"summary"}, &mon); err != nil {
+				return "", err
+			}
+			if summary, ok := mon.Summary.(*types.OpaqueNetworkSummary); ok && summary.OpaqueNetworkId == backing.OpaqueNetworkId {
+				return ref.Value, nil
+			}
+		}
+	}
+	return "", nil
+}
+
 // Suppress Diff on equal ip
 func suppressIpDifferences(k, old, new string, d *schema.ResourceData) bool {
 	o := net.ParseIP(old)
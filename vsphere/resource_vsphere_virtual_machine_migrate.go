@@ -6,6 +6,10 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/vim25/mo"
+	"golang.org/x/net/context"
 )
 
 func resourceVSphereVirtualMachineMigrateState(
@@ -15,10 +19,18 @@ func resourceVSphereVirtualMachineMigrateState(
 		return is, nil
 	}
 
+	var err error
 	switch v {
 	case 0:
 		log.Println("[INFO] Found Compute Instance State v0; migrating to v1")
-		is, err := migrateVSphereVirtualMachineStateV0toV1(is)
+		is, err = migrateVSphereVirtualMachineStateV0toV1(is)
+		if err != nil {
+			return is, err
+		}
+		fallthrough
+	case 1:
+		log.Println("[INFO] Found Compute Instance State v1; migrating to v2")
+		is, err = migrateVSphereVirtualMachineStateV1toV2(is, meta)
 		if err != nil {
 			return is, err
 		}
@@ -60,3 +72,46 @@ func migrateVSphereVirtualMachineStateV0toV1(is *terraform.InstanceState) (*terr
 	log.Printf("[DEBUG] Attributes after migration: %#v", is.Attributes)
 	return is, nil
 }
+
+// migrateVSphereVirtualMachineStateV1toV2 replaces the inventory path that was
+// used as the ID of the virtual machine with its instance UUID. The migration
+// fails without a vSphere client, so that it runs again once the provider is
+// configured. Only a virtual machine that does not exist keeps its old ID,
+// which Read then fails to find and removes from state.
+func migrateVSphereVirtualMachineStateV1toV2(is *terraform.InstanceState, meta interface{}) (*terraform.InstanceState, error) {
+	if is.Empty() || is.Attributes == nil {
+		log.Println("[DEBUG] Empty VSphere Virtual Machine State; nothing to migrate.")
+		return is, nil
+	}
+
+	client, ok := meta.(*govmomi.Client)
+	if !ok {
+		return is, fmt.Errorf("cannot migrate the ID of virtual machine %s without a vSphere client", is.ID)
+	}
+
+	dc, err := getDatacenter(client, is.Attributes["datacenter"])
+	if err != nil {
+		return is, fmt.Errorf("error finding datacenter for virtual machine %s: %s", is.ID, err)
+	}
+	finder := find.NewFinder(client.Client, true)
+	finder = finder.SetDatacenter(dc)
+
+	vm, err := finder.VirtualMachine(context.TODO(), is.ID)
+	if err != nil {
+		if _, ok := err.(*find.NotFoundError); ok {
+			log.Printf("[WARN] Virtual machine %s not found; not migrating its ID", is.ID)
+			return is, nil
+		}
+		return is, fmt.Errorf("error finding virtual machine %s: %s", is.ID, err)
+	}
+
+	var mvm mo.VirtualMachine
+	if err := vm.Properties(context.TODO(), vm.Reference(), []string{"config.instanceUuid"}, &mvm); err != nil {
+		return is, fmt.Errorf("error reading instance UUID of virtual machine %s: %s", is.ID, err)
+	}
+
+	log.Printf("[DEBUG] Migrating virtual machine ID %s to %s", is.ID, mvm.Config.InstanceUuid)
+	is.ID = mvm.Config.InstanceUuid
+	is.Attributes["id"] = mvm.Config.InstanceUuid
+	return is, nil
+}
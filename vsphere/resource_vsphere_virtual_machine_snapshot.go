@@ -0,0 +1,218 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+func resourceVSphereVirtualMachineSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereVirtualMachineSnapshotCreate,
+		Read:   resourceVSphereVirtualMachineSnapshotRead,
+		Update: resourceVSphereVirtualMachineSnapshotUpdate,
+		Delete: resourceVSphereVirtualMachineSnapshotDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereVirtualMachineSnapshotImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"virtual_machine_uuid": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"snapshot_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"memory": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				ForceNew: true,
+			},
+
+			"quiesce": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				ForceNew: true,
+			},
+
+			"remove_children": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"consolidate": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		},
+	}
+}
+
+func resourceVSphereVirtualMachineSnapshotCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	uuid := d.Get("virtual_machine_uuid").(string)
+
+	vm, err := virtualMachineFromUUID(client, uuid)
+	if err != nil {
+		return err
+	}
+
+	name := d.Get("snapshot_name").(string)
+	task, err := vm.CreateSnapshot(context.TODO(), name, d.Get("description").(string), d.Get("memory").(bool), d.Get("quiesce").(bool))
+	if err != nil {
+		return fmt.Errorf("error creating snapshot %s of virtual machine %s: %s", name, uuid, err)
+	}
+	info, err := task.WaitForResult(context.TODO(), nil)
+	if err != nil {
+		return fmt.Errorf("error creating snapshot %s of virtual machine %s: %s", name, uuid, err)
+	}
+	ref := info.Result.(types.ManagedObjectReference)
+	log.Printf("[INFO] Created snapshot %s of virtual machine %s", ref.Value, uuid)
+
+	d.SetId(ref.Value)
+
+	return resourceVSphereVirtualMachineSnapshotRead(d, meta)
+}
+
+func resourceVSphereVirtualMachineSnapshotRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	uuid := d.Get("virtual_machine_uuid").(string)
+
+	vm, err := virtualMachineFromUUID(client, uuid)
+	if err != nil {
+		if err == ErrVirtualMachineNotFound {
+			log.Printf("[DEBUG] virtual machine %s of snapshot %s not found", uuid, d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	var mvm mo.VirtualMachine
+	if err := vm.Properties(context.TODO(), vm.Reference(), []string{"snapshot"}, &mvm); err != nil {
+		return fmt.Errorf("error reading snapshots of virtual machine %s: %s", uuid, err)
+	}
+
+	var tree *types.VirtualMachineSnapshotTree
+	if mvm.Snapshot != nil {
+		tree = findSnapshotTree(mvm.Snapshot.RootSnapshotList, d.Id())
+	}
+	if tree == nil {
+		log.Printf("[DEBUG] snapshot %s of virtual machine %s not found", d.Id(), uuid)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("snapshot_name", tree.Name)
+	d.Set("description", tree.Description)
+	d.Set("quiesce", tree.Quiesced)
+
+	return nil
+}
+
+func resourceVSphereVirtualMachineSnapshotUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	if d.HasChange("snapshot_name") || d.HasChange("description") {
+		req := &types.RenameSnapshot{
+			This:        virtualMachineSnapshotReference(d.Id()),
+			Name:        d.Get("snapshot_name").(string),
+			Description: d.Get("description").(string),
+		}
+		if _, err := methods.RenameSnapshot(context.TODO(), client, req); err != nil {
+			return fmt.Errorf("error renaming snapshot %s: %s", d.Id(), err)
+		}
+	}
+
+	return resourceVSphereVirtualMachineSnapshotRead(d, meta)
+}
+
+func resourceVSphereVirtualMachineSnapshotDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	uuid := d.Get("virtual_machine_uuid").(string)
+
+	vm, err := virtualMachineFromUUID(client, uuid)
+	if err != nil {
+		return err
+	}
+
+	task, err := vm.RemoveSnapshot(context.TODO(), d.Id(), d.Get("remove_children").(bool), types.NewBool(d.Get("consolidate").(bool)))
+	if err != nil {
+		return fmt.Errorf("error removing snapshot %s of virtual machine %s: %s", d.Id(), uuid, err)
+	}
+	if err := task.Wait(context.TODO()); err != nil {
+		return fmt.Errorf("error removing snapshot %s of virtual machine %s: %s", d.Id(), uuid, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceVSphereVirtualMachineSnapshotImport imports a snapshot from an ID
+// in the form "<virtual_machine_uuid>:<snapshot>", where the snapshot is
+// given by its managed object ID, name or path in the snapshot tree.
+func resourceVSphereVirtualMachineSnapshotImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*govmomi.Client)
+
+	parts := strings.SplitN(d.Id(), ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid snapshot ID %q, expected <virtual_machine_uuid>:<snapshot>", d.Id())
+	}
+
+	vm, err := virtualMachineFromUUID(client, parts[0])
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := vm.FindSnapshot(context.TODO(), parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("error finding snapshot %s of virtual machine %s: %s", parts[1], parts[0], err)
+	}
+
+	d.Set("virtual_machine_uuid", parts[0])
+	d.Set("remove_children", false)
+	d.Set("consolidate", true)
+	d.SetId(snapshot.Reference().Value)
+	return []*schema.ResourceData{d}, nil
+}
+
+func virtualMachineSnapshotReference(id string) types.ManagedObjectReference {
+	return types.ManagedObjectReference{
+		Type:  "VirtualMachineSnapshot",
+		Value: id,
+	}
+}
+
+// findSnapshotTree returns the node of a snapshot tree with the given
+// managed object ID, or nil if there is none.
+func findSnapshotTree(trees []types.VirtualMachineSnapshotTree, id string) *types.VirtualMachineSnapshotTree {
+	for i := range trees {
+		if trees[i].Snapshot.Value == id {
+			return &trees[i]
+		}
+		if tree := findSnapshotTree(trees[i].ChildSnapshotList, id); tree != nil {
+			return tree
+		}
+	}
+	return nil
+}
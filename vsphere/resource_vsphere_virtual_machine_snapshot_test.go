@@ -0,0 +1,146 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+func TestAccVSphereVirtualMachineSnapshot_basic(t *testing.T) {
+	datacenter := os.Getenv("VSPHERE_DATACENTER")
+	vmName := os.Getenv("VSPHERE_SNAPSHOT_VM")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if vmName == "" {
+				t.Skip("set VSPHERE_SNAPSHOT_VM to run vsphere_virtual_machine_snapshot acceptance tests")
+			}
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereVirtualMachineSnapshotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereVirtualMachineSnapshotConfig, vmName, datacenter, "terraform-test-snapshot", "before upgrade"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereVirtualMachineSnapshotExists("vsphere_virtual_machine_snapshot.snapshot"),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine_snapshot.snapshot", "snapshot_name", "terraform-test-snapshot"),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine_snapshot.snapshot", "description", "before upgrade"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereVirtualMachineSnapshotConfig, vmName, datacenter, "terraform-test-snapshot-renamed", "after upgrade"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereVirtualMachineSnapshotExists("vsphere_virtual_machine_snapshot.snapshot"),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine_snapshot.snapshot", "snapshot_name", "terraform-test-snapshot-renamed"),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine_snapshot.snapshot", "description", "after upgrade"),
+				),
+			},
+		},
+	})
+}
+
+func TestFindSnapshotTree(t *testing.T) {
+	trees := []types.VirtualMachineSnapshotTree{
+		{
+			Snapshot: types.ManagedObjectReference{Type: "VirtualMachineSnapshot", Value: "snapshot-1"},
+			Name:     "root",
+			ChildSnapshotList: []types.VirtualMachineSnapshotTree{
+				{
+					Snapshot: types.ManagedObjectReference{Type: "VirtualMachineSnapshot", Value: "snapshot-2"},
+					Name:     "other",
+				},
+				{
+					Snapshot: types.ManagedObjectReference{Type: "VirtualMachineSnapshot", Value: "snapshot-3"},
+					Name:     "child",
+				},
+			},
+		},
+	}
+
+	tree := findSnapshotTree(trees, "snapshot-3")
+	if tree == nil || tree.Name != "child" {
+		t.Fatalf("expected snapshot child, got %#v", tree)
+	}
+	if tree := findSnapshotTree(trees, "snapshot-99"); tree != nil {
+		t.Fatalf("expected no snapshot, got %#v", tree)
+	}
+}
+
+func testAccCheckVSphereVirtualMachineSnapshotDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "vsphere_virtual_machine_snapshot" {
+			continue
+		}
+
+		found, err := testAccVirtualMachineHasSnapshot(rs.Primary.Attributes["virtual_machine_uuid"], rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if found {
+			return fmt.Errorf("snapshot %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckVSphereVirtualMachineSnapshotExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		found, err := testAccVirtualMachineHasSnapshot(rs.Primary.Attributes["virtual_machine_uuid"], rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("snapshot %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccVirtualMachineHasSnapshot(uuid, id string) (bool, error) {
+	client := testAccProvider.Meta().(*govmomi.Client)
+	vm, err := virtualMachineFromUUID(client, uuid)
+	if err != nil {
+		return false, err
+	}
+
+	var mvm mo.VirtualMachine
+	if err := vm.Properties(context.TODO(), vm.Reference(), []string{"snapshot"}, &mvm); err != nil {
+		return false, err
+	}
+	if mvm.Snapshot == nil {
+		return false, nil
+	}
+	return findSnapshotTree(mvm.Snapshot.RootSnapshotList, id) != nil, nil
+}
+
+const testAccCheckVSphereVirtualMachineSnapshotConfig = `
+data "vsphere_virtual_machine" "vm" {
+  name       = "%s"
+  datacenter = "%s"
+}
+
+resource "vsphere_virtual_machine_snapshot" "snapshot" {
+  virtual_machine_uuid = "${data.vsphere_virtual_machine.vm.uuid}"
+  snapshot_name        = "%s"
+  description          = "%s"
+}
+`
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,7 +14,9 @@ import (
 
 	"context"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/find"
@@ -21,6 +24,7 @@ import (
 	"github.com/vmware/govmomi/property"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/govmomi/vim25/xml"
 )
 
 ///////
@@ -297,6 +301,93 @@ func TestAccVSphereVirtualMachine_basic(t *testing.T) {
 	})
 }
 
+func TestAccVSphereVirtualMachine_importBasic(t *testing.T) {
+	basic_vars := setupTemplateBasicBodyVars()
+	config := basic_vars.testSprintfTemplateBody(testAccCheckVSphereVirtualMachineConfig_really_basic)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testBasicPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereVirtualMachineDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: config,
+			},
+			resource.TestStep{
+				ResourceName:     "vsphere_virtual_machine.foo",
+				ImportState:      true,
+				ImportStateCheck: testAccCheckVSphereVirtualMachineImportState("terraform-test", "2", "1024", "1"),
+			},
+		},
+	})
+}
+
+func TestAccVSphereVirtualMachine_importNestedFolder(t *testing.T) {
+	var folderLocationOpt string
+	if v := os.Getenv("VSPHERE_DATACENTER"); v != "" {
+		folderLocationOpt = fmt.Sprintf("    datacenter = \"%s\"\n", v)
+	}
+
+	folder := "tf_test_import/vm/nested"
+	data := setupTemplateFuncDHCPData()
+	vmName := "vsphere_virtual_machine.with_folder"
+	config := fmt.Sprintf(testAccCheckVSphereVirtualMachineConfig_createWithFolder,
+		folder,
+		folderLocationOpt,
+	) + data.parseDHCPTemplateConfig()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckVSphereVirtualMachineDestroy,
+			testAccCheckVSphereFolderDestroy,
+		),
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: config,
+			},
+			resource.TestStep{
+				ResourceName: vmName,
+				ImportState:  true,
+				ImportStateCheck: func(s []*terraform.InstanceState) error {
+					if len(s) != 1 {
+						return fmt.Errorf("expected 1 imported state, got %d", len(s))
+					}
+					if s[0].Attributes["folder"] != folder {
+						return fmt.Errorf("expected folder to be %q, got %q", folder, s[0].Attributes["folder"])
+					}
+					if s[0].Attributes["name"] != "terraform-test-with-folder" {
+						return fmt.Errorf("expected name to be terraform-test-with-folder, got %q", s[0].Attributes["name"])
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccCheckVSphereVirtualMachineImportState(name, vcpu, memory, disks string) resource.ImportStateCheckFunc {
+	return func(s []*terraform.InstanceState) error {
+		if len(s) != 1 {
+			return fmt.Errorf("expected 1 imported state, got %d", len(s))
+		}
+		expected := map[string]string{
+			"name":                name,
+			"vcpu":                vcpu,
+			"memory":              memory,
+			"disk.#":              disks,
+			"network_interface.#": "1",
+		}
+		for k, v := range expected {
+			if s[0].Attributes[k] != v {
+				return fmt.Errorf("expected %s to be %q, got %q", k, v, s[0].Attributes[k])
+			}
+		}
+		return nil
+	}
+}
+
 func TestAccVSphereVirtualMachine_noPanicShutdown(t *testing.T) {
 	var vm virtualMachine
 	basic_vars := setupTemplateBasicBodyVars()
@@ -330,6 +421,96 @@ func TestAccVSphereVirtualMachine_noPanicShutdown(t *testing.T) {
 	})
 }
 
+const testAccCheckVSphereVirtualMachineConfig_networkID = `
+data "vsphere_network" "net" {
+    name = "%s"
+%s
+}
+
+resource "vsphere_virtual_machine" "foo" {
+    name = "terraform-test"
+%s
+    vcpu = 2
+    memory = 1024
+    network_interface {
+        network_id = "${data.vsphere_network.net.id}"
+    }
+    disk {
+%s
+        template = "%s"
+    }
+}
+`
+
+func TestAccVSphereVirtualMachine_networkID(t *testing.T) {
+	basic_vars := setupTemplateBasicBodyVars()
+	var datacenterOpt string
+	if v := os.Getenv("VSPHERE_DATACENTER"); v != "" {
+		datacenterOpt = fmt.Sprintf("    datacenter = \"%s\"\n", v)
+	}
+	config := fmt.Sprintf(
+		testAccCheckVSphereVirtualMachineConfig_networkID,
+		basic_vars.label,
+		datacenterOpt,
+		basic_vars.locationOpt,
+		basic_vars.datastoreOpt,
+		basic_vars.template,
+	)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testBasicPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereVirtualMachineDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"vsphere_virtual_machine.foo", "network_interface.0.network_id",
+						"data.vsphere_network.net", "id"),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine.foo", "network_interface.0.label", basic_vars.label),
+				),
+			},
+		},
+	})
+}
+
+func TestAccVSphereVirtualMachine_renamedOutOfBand(t *testing.T) {
+	var vm virtualMachine
+	basic_vars := setupTemplateBasicBodyVars()
+	config := basic_vars.testSprintfTemplateBody(testAccCheckVSphereVirtualMachineConfig_really_basic)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testBasicPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereVirtualMachineDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					TestFuncData{vm: vm, label: basic_vars.label}.testCheckFuncBasic(),
+				),
+			},
+			resource.TestStep{
+				PreConfig: func() {
+					if err := testRenameVM("terraform-test", "terraform-test-renamed"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				PlanOnly:           true,
+				Config:             config,
+				ExpectNonEmptyPlan: true,
+			},
+			resource.TestStep{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					TestFuncData{vm: vm, label: basic_vars.label}.testCheckFuncBasic(),
+				),
+			},
+		},
+	})
+}
+
 const testAccCheckVSphereVirtualMachineConfig_hostname = `
 resource "vsphere_virtual_machine" "foo" {
     name = "terraform-test"
@@ -730,6 +911,72 @@ func TestAccVSphereVirtualMachine_createWithFolder(t *testing.T) {
 	})
 }
 
+const testAccCheckVSphereVirtualMachineConfig_renameAndMove = `
+resource "vsphere_folder" "first" {
+	path = "%s"
+%s
+}
+resource "vsphere_folder" "second" {
+	path = "%s"
+%s
+}
+resource "vsphere_virtual_machine" "rename_and_move" {
+    name = "%s"
+    folder = "${vsphere_folder.%s.path}"
+`
+
+func TestAccVSphereVirtualMachine_renameAndMove(t *testing.T) {
+	var vm virtualMachine
+	var folderLocationOpt string
+
+	if v := os.Getenv("VSPHERE_DATACENTER"); v != "" {
+		folderLocationOpt = fmt.Sprintf("    datacenter = \"%s\"\n", v)
+	}
+
+	firstFolder := "tf_test_renameAndMove_first"
+	secondFolder := "tf_test_renameAndMove_second"
+
+	data := setupTemplateFuncDHCPData()
+	vmName := "vsphere_virtual_machine.rename_and_move"
+	config := func(name, folder string) string {
+		return fmt.Sprintf(testAccCheckVSphereVirtualMachineConfig_renameAndMove,
+			firstFolder,
+			folderLocationOpt,
+			secondFolder,
+			folderLocationOpt,
+			name,
+			folder,
+		) + data.parseDHCPTemplateConfig()
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckVSphereVirtualMachineDestroy,
+			testAccCheckVSphereFolderDestroy,
+		),
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: config("terraform-test-rename", "first"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereVirtualMachineExists(vmName, &vm),
+					resource.TestCheckResourceAttr(vmName, "name", "terraform-test-rename"),
+					resource.TestCheckResourceAttr(vmName, "folder", firstFolder),
+				),
+			},
+			resource.TestStep{
+				Config: config("terraform-test-renamed", "second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereVirtualMachineExists(vmName, &vm),
+					resource.TestCheckResourceAttr(vmName, "name", "terraform-test-renamed"),
+					resource.TestCheckResourceAttr(vmName, "folder", secondFolder),
+				),
+			},
+		},
+	})
+}
+
 const testAccCheckVsphereVirtualMachineConfig_cdrom = `
 resource "vsphere_virtual_machine" "with_cdrom" {
     name = "terraform-test-with-cdrom"
@@ -934,6 +1181,61 @@ func TestAccVSphereVirtualMachine_updateVcpu(t *testing.T) {
 	})
 }
 
+const testAccCheckVSphereVirtualMachineConfig_hotAdd = `
+resource "vsphere_virtual_machine" "bar" {
+    name = "terraform-test"
+%s
+    vcpu = %s
+    memory = 1024
+    cpu_hot_add_enabled = true
+    memory_hot_add_enabled = true
+    force_power_off = false
+    network_interface {
+        label = "%s"
+    }
+    disk {
+%s
+        template = "%s"
+    }
+}
+`
+
+func TestAccVSphereVirtualMachine_hotAddVcpu(t *testing.T) {
+	var vm virtualMachine
+	data := setupTemplateFuncDHCPData()
+	log.Printf("[DEBUG] template= %s", testAccCheckVSphereVirtualMachineConfig_hotAdd)
+
+	config := data.testSprintfDHCPTemplateBodySecondArgDynamic(testAccCheckVSphereVirtualMachineConfig_hotAdd, "2")
+	log.Printf("[DEBUG] template config= %s", config)
+
+	configUpdate := data.testSprintfDHCPTemplateBodySecondArgDynamic(testAccCheckVSphereVirtualMachineConfig_hotAdd, "4")
+	log.Printf("[DEBUG] template configUpdate= %s", configUpdate)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereVirtualMachineDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.ComposeTestCheckFunc(
+						TestFuncData{vm: vm, label: data.label, vmName: "vsphere_virtual_machine.bar"}.testCheckFuncBasic(),
+					),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine.bar", "cpu_hot_add_enabled", "true"),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine.bar", "memory_hot_add_enabled", "true"),
+				),
+			},
+			resource.TestStep{
+				Config: configUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					TestFuncData{vm: vm, label: data.label, vmName: "vsphere_virtual_machine.bar", numCPU: "4"}.testCheckFuncBasic(),
+				),
+			},
+		},
+	})
+}
+
 const testAccCheckVSphereVirtualMachineConfig_ipv6 = `
 resource "vsphere_virtual_machine" "ipv6" {
     name = "terraform-test-ipv6"
@@ -1257,6 +1559,29 @@ func testPowerOffVM(name string) error {
 	return nil
 }
 
+// testRenameVM renames a virtual machine outside of Terraform, to check that
+// the resource is still tracked by its UUID afterwards.
+func testRenameVM(name, newName string) error {
+	client := testAccProvider.Meta().(*govmomi.Client)
+	finder := find.NewFinder(client.Client, true)
+
+	dc, err := getDatacenter(client, os.Getenv("VSPHERE_DATACENTER"))
+	if err != nil {
+		return fmt.Errorf("error fetching datacenter: %s", err)
+	}
+	finder = finder.SetDatacenter(dc)
+
+	vm, err := finder.VirtualMachine(context.TODO(), name)
+	if err != nil {
+		return err
+	}
+	task, err := vm.Rename(context.TODO(), newName)
+	if err != nil {
+		return fmt.Errorf("error renaming VM: %s", err)
+	}
+	return task.Wait(context.TODO())
+}
+
 func testAccCheckVSphereVirtualMachineDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*govmomi.Client)
 	finder := find.NewFinder(client.Client, true)
@@ -1438,6 +1763,10 @@ func testAccCheckVSphereVirtualMachineExists(n string, vm *virtualMachine) resou
 
 		_, err = object.NewSearchIndex(client.Client).FindChild(context.TODO(), folder, rs.Primary.Attributes["name"])
 
+		if _, err := virtualMachineFromUUID(client, rs.Primary.ID); err != nil {
+			return fmt.Errorf("error finding virtual machine by UUID %s: %s", rs.Primary.ID, err)
+		}
+
 		*vm = virtualMachine{
 			name: rs.Primary.ID,
 		}
@@ -1642,6 +1971,132 @@ func TestAccVSphereVirtualMachine_annotation(t *testing.T) {
 	})
 }
 
+const testAccCheckVSphereVirtualMachineConfig_resourceAllocation = `
+resource "vsphere_virtual_machine" "car" {
+    name = "terraform-test-resource-allocation"
+    num_cores_per_socket = 2
+    cpu_share_level = "high"
+    cpu_limit = 4000
+    memory_share_level = "custom"
+    memory_shares = %d
+    memory_reservation = %d
+`
+
+func TestAccVSphereVirtualMachine_resourceAllocation(t *testing.T) {
+	data := setupTemplateFuncDHCPData()
+	vmName := "vsphere_virtual_machine.car"
+	config := func(shares, reservation int) string {
+		return fmt.Sprintf(testAccCheckVSphereVirtualMachineConfig_resourceAllocation, shares, reservation) +
+			data.parseDHCPTemplateConfigWithTemplate(testAccCheckVSphereTemplate_dhcp)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereVirtualMachineDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: config(20000, 512),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(vmName, "num_cores_per_socket", "2"),
+					resource.TestCheckResourceAttr(vmName, "cpu_share_level", "high"),
+					resource.TestCheckResourceAttr(vmName, "cpu_limit", "4000"),
+					resource.TestCheckResourceAttr(vmName, "memory_shares", "20000"),
+					resource.TestCheckResourceAttr(vmName, "memory_reservation", "512"),
+				),
+			},
+			resource.TestStep{
+				Config: config(30000, 1024),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(vmName, "memory_shares", "30000"),
+					resource.TestCheckResourceAttr(vmName, "memory_reservation", "1024"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCheckVSphereVirtualMachineConfig_efi = `
+resource "vsphere_virtual_machine" "efi" {
+    name = "terraform-test-efi"
+%s
+    vcpu = 2
+    memory = 2048
+    guest_id = "windows9Server64Guest"
+    firmware = "efi"
+    efi_secure_boot_enabled = true
+    hardware_version = %d
+    scsi_type = "lsilogic-sas"
+    network_interface {
+        label = "%s"
+    }
+    disk {
+        size = 1
+        name = "efi.vmdk"
+%s
+    }
+}
+`
+
+func TestAccVSphereVirtualMachine_efi(t *testing.T) {
+	data := setupTemplateFuncDHCPData()
+	vmName := "vsphere_virtual_machine.efi"
+	config := func(version int) string {
+		return fmt.Sprintf(testAccCheckVSphereVirtualMachineConfig_efi, data.locationOpt, version, data.label, data.datastoreOpt)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereVirtualMachineDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: config(11),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(vmName, "guest_id", "windows9Server64Guest"),
+					resource.TestCheckResourceAttr(vmName, "firmware", "efi"),
+					resource.TestCheckResourceAttr(vmName, "efi_secure_boot_enabled", "true"),
+					resource.TestCheckResourceAttr(vmName, "hardware_version", "11"),
+					resource.TestCheckResourceAttr(vmName, "scsi_type", "lsilogic-sas"),
+				),
+			},
+			resource.TestStep{
+				Config: config(13),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(vmName, "hardware_version", "13"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCheckVSphereVirtualMachineConfig_cloneHardwareVersion = `
+resource "vsphere_virtual_machine" "hardware" {
+    name = "terraform-test-hardware-version"
+    hardware_version = 13
+`
+
+func TestAccVSphereVirtualMachine_cloneHardwareVersion(t *testing.T) {
+	data := setupTemplateFuncDHCPData()
+	vmName := "vsphere_virtual_machine.hardware"
+	config := testAccCheckVSphereVirtualMachineConfig_cloneHardwareVersion +
+		data.parseDHCPTemplateConfigWithTemplate(testAccCheckVSphereTemplate_dhcp)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereVirtualMachineDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(vmName, "hardware_version", "13"),
+				),
+			},
+		},
+	})
+}
+
 func vmCleanup(dc *object.Datacenter, ds *object.Datastore, vmName string) error {
 	client := testAccProvider.Meta().(*govmomi.Client)
 	fileManager := object.NewFileManager(client.Client)
@@ -1703,3 +2158,233 @@ func checkForDisk(datacenter string, datastore string, vmName string, path strin
 		return nil
 	}
 }
+
+func TestDiskChanges(t *testing.T) {
+	diskSchema := resourceVSphereVirtualMachine().Schema["disk"].Elem.(*schema.Resource)
+	disk := func(key int, name, datastore string) map[string]interface{} {
+		return map[string]interface{}{
+			"uuid":            "",
+			"key":             key,
+			"template":        "",
+			"type":            "thin",
+			"datastore":       datastore,
+			"size":            10,
+			"name":            name,
+			"iops":            0,
+			"vmdk":            "",
+			"bootable":        false,
+			"keep_on_remove":  false,
+			"controller_type": "scsi",
+		}
+	}
+
+	o := schema.NewSet(schema.HashResource(diskSchema), []interface{}{
+		disk(2000, "data", "ds1"),
+		disk(2001, "logs", "ds1"),
+		disk(2002, "scratch", "ds1"),
+	})
+	n := schema.NewSet(schema.HashResource(diskSchema), []interface{}{
+		disk(0, "data", "ds2"),
+		disk(2001, "logs", "ds1"),
+		disk(0, "cache", "ds1"),
+	})
+
+	added, removed, moved := diskChanges(o, n)
+	if len(moved) != 1 || moved[0].key != 2000 || moved[0].datastore != "ds2" {
+		t.Fatalf("expected disk 2000 to move to ds2, got %#v", moved)
+	}
+	if len(added) != 1 || added[0].(map[string]interface{})["name"] != "cache" {
+		t.Fatalf("expected the cache disk to be added, got %#v", added)
+	}
+	if len(removed) != 1 || removed[0].(map[string]interface{})["name"] != "scratch" {
+		t.Fatalf("expected the scratch disk to be removed, got %#v", removed)
+	}
+}
+
+func TestVirtualMachineResourceAllocation(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceVSphereVirtualMachine().Schema, map[string]interface{}{
+		"cpu_share_level":    "custom",
+		"cpu_shares":         4000,
+		"memory_reservation": 2048,
+	})
+
+	cpu := virtualMachineResourceAllocation(d, "cpu")
+	if cpu.Shares.Level != types.SharesLevelCustom || cpu.Shares.Shares != 4000 {
+		t.Fatalf("unexpected CPU shares %#v", cpu.Shares)
+	}
+	if cpu.Limit != -1 || cpu.ExpandableReservation != nil {
+		t.Fatalf("unexpected CPU allocation %#v", cpu)
+	}
+
+	memory := virtualMachineResourceAllocation(d, "memory")
+	if memory.Reservation != 2048 || memory.Shares.Level != types.SharesLevelNormal {
+		t.Fatalf("unexpected memory allocation %#v", memory)
+	}
+}
+
+func TestVirtualMachineResourceAllocation_zeroReservation(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceVSphereVirtualMachine().Schema, map[string]interface{}{
+		"memory_reservation": 0,
+	})
+	spec := types.VirtualMachineConfigSpec{
+		MemoryAllocation: virtualMachineResourceAllocation(d, "memory"),
+	}
+
+	b, err := xml.Marshal(spec)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	out := string(b)
+	expected := `type="ResourceAllocationInfo"><reservation>0</reservation><limit>-1</limit>`
+	if !strings.Contains(out, expected) {
+		t.Fatalf("expected %s in %s", expected, out)
+	}
+}
+
+func TestVirtualMachineSCSIType(t *testing.T) {
+	devices := object.VirtualDeviceList{
+		&types.VirtualIDEController{},
+		&types.ParaVirtualSCSIController{},
+		&types.VirtualLsiLogicController{},
+	}
+	if v := virtualMachineSCSIType(devices); v != "pvscsi" {
+		t.Fatalf("expected pvscsi, got %q", v)
+	}
+	if v := virtualMachineSCSIType(object.VirtualDeviceList{&types.VirtualIDEController{}}); v != "" {
+		t.Fatalf("expected no SCSI type, got %q", v)
+	}
+}
+
+func TestChangeSCSIControllerType(t *testing.T) {
+	controller := &types.VirtualLsiLogicController{}
+	controller.Key = 1000
+	controller.BusNumber = 0
+	scsiDisk := &types.VirtualDisk{}
+	scsiDisk.Key = 2000
+	scsiDisk.ControllerKey = 1000
+	ideDisk := &types.VirtualDisk{}
+	ideDisk.Key = 3000
+	ideDisk.ControllerKey = 200
+	devices := object.VirtualDeviceList{controller, scsiDisk, ideDisk}
+
+	changes, err := changeSCSIControllerType(devices, "pvscsi")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 device changes, got %d", len(changes))
+	}
+
+	remove := changes[0].GetVirtualDeviceConfigSpec()
+	if remove.Operation != types.VirtualDeviceConfigSpecOperationRemove || remove.Device != controller {
+		t.Fatalf("expected the old controller to be removed, got %#v", remove)
+	}
+	add := changes[1].GetVirtualDeviceConfigSpec()
+	scsi, ok := add.Device.(*types.ParaVirtualSCSIController)
+	if add.Operation != types.VirtualDeviceConfigSpecOperationAdd || !ok {
+		t.Fatalf("expected a pvscsi controller to be added, got %#v", add)
+	}
+	if scsi.BusNumber != 0 {
+		t.Fatalf("expected the new controller on bus 0, got %d", scsi.BusNumber)
+	}
+	edit := changes[2].GetVirtualDeviceConfigSpec()
+	if edit.Operation != types.VirtualDeviceConfigSpecOperationEdit || edit.Device != scsiDisk {
+		t.Fatalf("expected the SCSI disk to be edited, got %#v", edit)
+	}
+	if scsiDisk.ControllerKey != scsi.Key || ideDisk.ControllerKey != 200 {
+		t.Fatalf("unexpected controller keys %d and %d", scsiDisk.ControllerKey, ideDisk.ControllerKey)
+	}
+
+	if _, err := changeSCSIControllerType(object.VirtualDeviceList{ideDisk}, "pvscsi"); err == nil {
+		t.Fatal("expected an error for a virtual machine without a SCSI controller")
+	}
+}
+
+// TestResourceVSphereVirtualMachineDiff_noSCSIType checks that state written
+// before scsi_type existed plans no changes.
+func TestResourceVSphereVirtualMachineDiff_noSCSIType(t *testing.T) {
+	raw := map[string]interface{}{
+		"name":   "terraform-test",
+		"vcpu":   2,
+		"memory": 1024,
+		"network_interface": []interface{}{
+			map[string]interface{}{"label": "VM Network"},
+		},
+		"disk": []interface{}{
+			map[string]interface{}{"template": "centos7"},
+		},
+	}
+	r := resourceVSphereVirtualMachine()
+	d := schema.TestResourceDataRaw(t, r.Schema, raw)
+	d.SetId("42")
+	state := d.State()
+	delete(state.Attributes, "scsi_type")
+
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	diff, err := r.Diff(state, terraform.NewResourceConfig(c))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !diff.Empty() {
+		t.Fatalf("expected an empty plan, got %#v", diff.Attributes)
+	}
+}
+
+func TestHardwareVersionUpgradeRequired(t *testing.T) {
+	cases := []struct {
+		current string
+		version int
+		upgrade bool
+		err     bool
+	}{
+		{"vmx-11", 13, true, false},
+		{"vmx-13", 13, false, false},
+		{"vmx-13", 11, false, true},
+		{"13", 13, false, true},
+	}
+
+	for _, tc := range cases {
+		upgrade, err := hardwareVersionUpgradeRequired(tc.current, tc.version)
+		if (err != nil) != tc.err {
+			t.Fatalf("%s to %d: unexpected error state: %v", tc.current, tc.version, err)
+		}
+		if upgrade != tc.upgrade {
+			t.Fatalf("%s to %d: expected upgrade %t, got %t", tc.current, tc.version, tc.upgrade, upgrade)
+		}
+	}
+}
+
+func TestSetGuestAndHardware(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceVSphereVirtualMachine().Schema, map[string]interface{}{})
+	err := setGuestAndHardware(d, &types.VirtualMachineConfigInfo{
+		GuestId:  "centos64Guest",
+		Firmware: "efi",
+		Version:  "vmx-13",
+		BootOptions: &types.VirtualMachineBootOptions{
+			EfiSecureBootEnabled: types.NewBool(true),
+		},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if v := d.Get("guest_id").(string); v != "centos64Guest" {
+		t.Fatalf("expected guest_id centos64Guest, got %s", v)
+	}
+	if v := d.Get("firmware").(string); v != "efi" {
+		t.Fatalf("expected firmware efi, got %s", v)
+	}
+	if v := d.Get("hardware_version").(int); v != 13 {
+		t.Fatalf("expected hardware_version 13, got %d", v)
+	}
+	if !d.Get("efi_secure_boot_enabled").(bool) {
+		t.Fatal("expected efi_secure_boot_enabled to be set")
+	}
+
+	if err := setGuestAndHardware(d, &types.VirtualMachineConfigInfo{Version: "bogus"}); err == nil {
+		t.Fatal("expected an error for an invalid hardware version")
+	}
+}
@@ -0,0 +1,251 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+func resourceVSphereVmfsDatastore() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereVmfsDatastoreCreate,
+		Read:   resourceVSphereVmfsDatastoreRead,
+		Update: resourceVSphereVmfsDatastoreUpdate,
+		Delete: resourceVSphereVmfsDatastoreDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereVmfsDatastoreImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"host_system_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"disks": &schema.Schema{
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"url": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			// Capacity in MB
+			"capacity": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			// Free space in MB
+			"free_space": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceVSphereVmfsDatastoreCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	hostID := d.Get("host_system_id").(string)
+	name := d.Get("name").(string)
+	disks := stringList(d.Get("disks").([]interface{}))
+
+	dss, err := hostDatastoreSystemFromHostSystemID(client, hostID)
+	if err != nil {
+		return err
+	}
+
+	path, err := vmfsDiskDevicePath(dss, disks[0])
+	if err != nil {
+		return err
+	}
+	options, err := dss.QueryVmfsDatastoreCreateOptions(context.TODO(), path)
+	if err != nil {
+		return fmt.Errorf("error querying create options for disk %s: %s", disks[0], err)
+	}
+	if len(options) == 0 {
+		return fmt.Errorf("no options to create a VMFS datastore on disk %s", disks[0])
+	}
+	spec, ok := options[0].Spec.(*types.VmfsDatastoreCreateSpec)
+	if !ok {
+		return fmt.Errorf("unexpected create specification for disk %s: %T", disks[0], options[0].Spec)
+	}
+	spec.Vmfs.VolumeName = name
+
+	ds, err := dss.CreateVmfsDatastore(context.TODO(), *spec)
+	if err != nil {
+		return fmt.Errorf("error creating VMFS datastore %s: %s", name, err)
+	}
+	d.SetId(ds.Reference().Value)
+	log.Printf("[INFO] Created VMFS datastore %s", d.Id())
+
+	for _, disk := range disks[1:] {
+		if err := vmfsDatastoreExtend(client, dss, ds, disk); err != nil {
+			return err
+		}
+	}
+
+	return resourceVSphereVmfsDatastoreRead(d, meta)
+}
+
+func resourceVSphereVmfsDatastoreRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	mds, err := datastoreProperties(client, d.Id())
+	if err != nil {
+		if isManagedObjectNotFoundError(err) {
+			log.Printf("[DEBUG] VMFS datastore %s not found", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("error reading VMFS datastore %s: %s", d.Id(), err)
+	}
+
+	info, ok := mds.Info.(*types.VmfsDatastoreInfo)
+	if !ok || info.Vmfs == nil {
+		return fmt.Errorf("datastore %s is not a VMFS datastore", d.Id())
+	}
+
+	d.Set("name", mds.Summary.Name)
+	if len(mds.Host) > 0 {
+		d.Set("host_system_id", mds.Host[0].Key.Value)
+	}
+
+	// A disk can hold more than one extent of the same datastore.
+	var disks []string
+	seen := make(map[string]bool)
+	for _, extent := range info.Vmfs.Extent {
+		if !seen[extent.DiskName] {
+			seen[extent.DiskName] = true
+			disks = append(disks, extent.DiskName)
+		}
+	}
+	if err := d.Set("disks", disks); err != nil {
+		return fmt.Errorf("Invalid disks to set: %#v", disks)
+	}
+
+	d.Set("url", mds.Summary.Url)
+	d.Set("capacity", mds.Summary.Capacity/1024/1024)
+	d.Set("free_space", mds.Summary.FreeSpace/1024/1024)
+
+	return nil
+}
+
+func resourceVSphereVmfsDatastoreUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	if d.HasChange("name") {
+		if err := datastoreRename(client, d.Id(), d.Get("name").(string)); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("disks") {
+		o, n := d.GetChange("disks")
+		oldDisks := schema.NewSet(schema.HashString, o.([]interface{}))
+		newDisks := schema.NewSet(schema.HashString, n.([]interface{}))
+		if removed := oldDisks.Difference(newDisks); removed.Len() > 0 {
+			return fmt.Errorf("disks cannot be removed from VMFS datastore %s: %v", d.Id(), removed.List())
+		}
+
+		dss, err := hostDatastoreSystemFromHostSystemID(client, d.Get("host_system_id").(string))
+		if err != nil {
+			return err
+		}
+		ds := object.NewDatastore(client.Client, datastoreReference(d.Id()))
+		for _, disk := range n.([]interface{}) {
+			if oldDisks.Contains(disk) {
+				continue
+			}
+			if err := vmfsDatastoreExtend(client, dss, ds, disk.(string)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return resourceVSphereVmfsDatastoreRead(d, meta)
+}
+
+func resourceVSphereVmfsDatastoreDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+
+	if err := datastoreUnmount(client, d.Get("host_system_id").(string), d.Id()); err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceVSphereVmfsDatastoreImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	return datastoreImport(d, meta)
+}
+
+// vmfsDiskDevicePath returns the device path of a disk, given its canonical
+// name, if the disk is available for a new VMFS datastore or extent.
+func vmfsDiskDevicePath(dss *object.HostDatastoreSystem, name string) (string, error) {
+	disks, err := dss.QueryAvailableDisksForVmfs(context.TODO())
+	if err != nil {
+		return "", fmt.Errorf("error querying available disks: %s", err)
+	}
+	for _, disk := range disks {
+		if disk.CanonicalName == name {
+			return disk.DevicePath, nil
+		}
+	}
+	return "", fmt.Errorf("disk %s is not available for VMFS", name)
+}
+
+// vmfsDatastoreExtend adds an extent on the given disk to a datastore.
+func vmfsDatastoreExtend(client *govmomi.Client, dss *object.HostDatastoreSystem, ds *object.Datastore, disk string) error {
+	path, err := vmfsDiskDevicePath(dss, disk)
+	if err != nil {
+		return err
+	}
+
+	req := &types.QueryVmfsDatastoreExtendOptions{
+		This:                     dss.Reference(),
+		Datastore:                ds.Reference(),
+		DevicePath:               path,
+		SuppressExpandCandidates: types.NewBool(true),
+	}
+	res, err := methods.QueryVmfsDatastoreExtendOptions(context.TODO(), client, req)
+	if err != nil {
+		return fmt.Errorf("error querying extend options for disk %s: %s", disk, err)
+	}
+	if len(res.Returnval) == 0 {
+		return fmt.Errorf("no options to extend datastore %s onto disk %s", ds.Reference().Value, disk)
+	}
+	spec, ok := res.Returnval[0].Spec.(*types.VmfsDatastoreExtendSpec)
+	if !ok {
+		return fmt.Errorf("unexpected extend specification for disk %s: %T", disk, res.Returnval[0].Spec)
+	}
+
+	extend := &types.ExtendVmfsDatastore{
+		This:      dss.Reference(),
+		Datastore: ds.Reference(),
+		Spec:      *spec,
+	}
+	if _, err := methods.ExtendVmfsDatastore(context.TODO(), client, extend); err != nil {
+		return fmt.Errorf("error extending datastore %s onto disk %s: %s", ds.Reference().Value, disk, err)
+	}
+	log.Printf("[INFO] Extended VMFS datastore %s onto disk %s", ds.Reference().Value, disk)
+	return nil
+}
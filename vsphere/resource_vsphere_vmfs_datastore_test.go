@@ -0,0 +1,63 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccVSphereVmfsDatastore_basic(t *testing.T) {
+	host := os.Getenv("VSPHERE_HOST_SYSTEM_ID")
+	disk0 := os.Getenv("VSPHERE_VMFS_DISK0")
+	disk1 := os.Getenv("VSPHERE_VMFS_DISK1")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccVSphereVmfsDatastorePreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereDatastoreDestroy("vsphere_vmfs_datastore"),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereVmfsDatastoreConfig, host, fmt.Sprintf("%q", disk0)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereDatastoreExists("vsphere_vmfs_datastore.vmfs"),
+					resource.TestCheckResourceAttr("vsphere_vmfs_datastore.vmfs", "disks.#", "1"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereVmfsDatastoreConfig, host, fmt.Sprintf("%q, %q", disk0, disk1)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereDatastoreExists("vsphere_vmfs_datastore.vmfs"),
+					resource.TestCheckResourceAttr("vsphere_vmfs_datastore.vmfs", "disks.#", "2"),
+				),
+			},
+			{
+				ResourceName:            "vsphere_vmfs_datastore.vmfs",
+				ImportState:             true,
+				ImportStateId:           fmt.Sprintf("/%s/datastore/terraform-test-vmfs", os.Getenv("VSPHERE_DATACENTER")),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"free_space"},
+			},
+		},
+	})
+}
+
+func testAccVSphereVmfsDatastorePreCheck(t *testing.T) {
+	for _, k := range []string{"VSPHERE_DATACENTER", "VSPHERE_HOST_SYSTEM_ID", "VSPHERE_VMFS_DISK0", "VSPHERE_VMFS_DISK1"} {
+		if v := os.Getenv(k); v == "" {
+			t.Fatalf("env variable %s must be set for acceptance tests", k)
+		}
+	}
+}
+
+const testAccCheckVSphereVmfsDatastoreConfig = `
+resource "vsphere_vmfs_datastore" "vmfs" {
+  name           = "terraform-test-vmfs"
+  host_system_id = "%s"
+  disks          = [%s]
+}
+`
@@ -0,0 +1,488 @@
+package vsphere
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+	"golang.org/x/net/context"
+)
+
+var vnicServices = []string{
+	string(types.HostVirtualNicManagerNicTypeVmotion),
+	string(types.HostVirtualNicManagerNicTypeFaultToleranceLogging),
+	string(types.HostVirtualNicManagerNicTypeVSphereReplication),
+	string(types.HostVirtualNicManagerNicTypeVSphereReplicationNFC),
+	string(types.HostVirtualNicManagerNicTypeManagement),
+	string(types.HostVirtualNicManagerNicTypeVsan),
+	string(types.HostVirtualNicManagerNicTypeVSphereProvisioning),
+}
+
+func resourceVSphereVNIC() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVSphereVNICCreate,
+		Read:   resourceVSphereVNICRead,
+		Update: resourceVSphereVNICUpdate,
+		Delete: resourceVSphereVNICDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceVSphereVNICImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"host_system_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"portgroup": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"distributed_virtual_switch_uuid", "distributed_port_group_key"},
+			},
+
+			"distributed_virtual_switch_uuid": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"portgroup"},
+			},
+
+			"distributed_port_group_key": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"portgroup"},
+			},
+
+			"ipv4": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"dhcp": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+
+						"ip": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+
+						"netmask": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+
+			"ipv6": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"dhcp": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+
+						"autoconfig": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+
+						"addresses": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+
+			"mac": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"mtu": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  1500,
+			},
+
+			"netstack": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "defaultTcpipStack",
+				ForceNew: true,
+			},
+
+			"services": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateStringInSlice(vnicServices),
+				},
+			},
+
+			"device": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceVSphereVNICCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	hostID := d.Get("host_system_id").(string)
+
+	_, hasPortgroup := d.GetOk("portgroup")
+	_, hasDVPortgroup := d.GetOk("distributed_port_group_key")
+	if !hasPortgroup && !hasDVPortgroup {
+		return fmt.Errorf("One of portgroup or distributed_port_group_key must be set.")
+	}
+	_, hasIPv4 := d.GetOk("ipv4")
+	_, hasIPv6 := d.GetOk("ipv6")
+	if !hasIPv4 && !hasIPv6 {
+		return fmt.Errorf("One of ipv4 or ipv6 must be set.")
+	}
+
+	ns, err := hostNetworkSystemFromHostSystemID(client, hostID)
+	if err != nil {
+		return err
+	}
+
+	spec, err := vnicSpec(d)
+	if err != nil {
+		return err
+	}
+	spec.Mac = d.Get("mac").(string)
+	spec.NetStackInstanceKey = d.Get("netstack").(string)
+	if v, ok := d.GetOk("distributed_port_group_key"); ok {
+		spec.DistributedVirtualPort = &types.DistributedVirtualSwitchPortConnection{
+			SwitchUuid:   d.Get("distributed_virtual_switch_uuid").(string),
+			PortgroupKey: v.(string),
+		}
+	}
+
+	device, err := ns.AddVirtualNic(context.TODO(), d.Get("portgroup").(string), *spec)
+	if err != nil {
+		return fmt.Errorf("error adding VMkernel adapter to host %s: %s", hostID, err)
+	}
+	log.Printf("[INFO] Added VMkernel adapter %s to host %s", device, hostID)
+
+	d.SetId(hostNetworkID(hostID, device))
+
+	if err := vnicSelectServices(client, hostID, device, nil, d.Get("services").(*schema.Set).List()); err != nil {
+		return err
+	}
+
+	return resourceVSphereVNICRead(d, meta)
+}
+
+func resourceVSphereVNICRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	hostID, device, err := splitHostNetworkID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	vnic, err := vnicFromDevice(client, hostID, device)
+	if err != nil {
+		return err
+	}
+	if vnic == nil {
+		log.Printf("[DEBUG] VMkernel adapter %s not found on host %s", device, hostID)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("host_system_id", hostID)
+	d.Set("device", device)
+	d.Set("portgroup", vnic.Portgroup)
+	if dvp := vnic.Spec.DistributedVirtualPort; dvp != nil {
+		d.Set("distributed_virtual_switch_uuid", dvp.SwitchUuid)
+		d.Set("distributed_port_group_key", dvp.PortgroupKey)
+	}
+	d.Set("mac", vnic.Spec.Mac)
+	d.Set("mtu", vnic.Spec.Mtu)
+	d.Set("netstack", vnic.Spec.NetStackInstanceKey)
+
+	var ipv4, ipv6 []map[string]interface{}
+	if ip := vnic.Spec.Ip; ip != nil {
+		if ip.Dhcp || ip.IpAddress != "" {
+			ipv4 = append(ipv4, map[string]interface{}{
+				"dhcp":    ip.Dhcp,
+				"ip":      ip.IpAddress,
+				"netmask": ip.SubnetMask,
+			})
+		}
+		if v6 := ip.IpV6Config; v6 != nil {
+			addresses := vnicIPv6Addresses(v6)
+			dhcp := v6.DhcpV6Enabled != nil && *v6.DhcpV6Enabled
+			autoconfig := v6.AutoConfigurationEnabled != nil && *v6.AutoConfigurationEnabled
+			if dhcp || autoconfig || len(addresses) > 0 {
+				ipv6 = append(ipv6, map[string]interface{}{
+					"dhcp":       dhcp,
+					"autoconfig": autoconfig,
+					"addresses":  addresses,
+				})
+			}
+		}
+	}
+	if err := d.Set("ipv4", ipv4); err != nil {
+		return fmt.Errorf("Invalid ipv4 to set: %#v", ipv4)
+	}
+	if err := d.Set("ipv6", ipv6); err != nil {
+		return fmt.Errorf("Invalid ipv6 to set: %#v", ipv6)
+	}
+
+	services, err := vnicServicesForDevice(client, hostID, device)
+	if err != nil {
+		return err
+	}
+	if err := d.Set("services", services); err != nil {
+		return fmt.Errorf("Invalid services to set: %#v", services)
+	}
+
+	return nil
+}
+
+func resourceVSphereVNICUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	hostID, device, err := splitHostNetworkID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	if d.HasChange("ipv4") || d.HasChange("ipv6") || d.HasChange("mtu") {
+		ns, err := hostNetworkSystemFromHostSystemID(client, hostID)
+		if err != nil {
+			return err
+		}
+		spec, err := vnicSpec(d)
+		if err != nil {
+			return err
+		}
+
+		// IPv6 addresses are changed by removing the old ones and adding the
+		// new ones.
+		if d.HasChange("ipv6") && spec.Ip.IpV6Config != nil {
+			old, _ := d.GetChange("ipv6")
+			oldAddresses, err := expandVNICIPv6Addresses(old.([]interface{}), string(types.HostConfigChangeOperationRemove))
+			if err != nil {
+				return err
+			}
+			spec.Ip.IpV6Config.IpV6Address = append(oldAddresses, spec.Ip.IpV6Config.IpV6Address...)
+		}
+
+		if err := ns.UpdateVirtualNic(context.TODO(), device, *spec); err != nil {
+			return fmt.Errorf("error updating VMkernel adapter %s on host %s: %s", device, hostID, err)
+		}
+	}
+
+	if d.HasChange("services") {
+		o, n := d.GetChange("services")
+		if err := vnicSelectServices(client, hostID, device, o.(*schema.Set).List(), n.(*schema.Set).List()); err != nil {
+			return err
+		}
+	}
+
+	return resourceVSphereVNICRead(d, meta)
+}
+
+func resourceVSphereVNICDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*govmomi.Client)
+	hostID, device, err := splitHostNetworkID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	ns, err := hostNetworkSystemFromHostSystemID(client, hostID)
+	if err != nil {
+		return err
+	}
+
+	if err := ns.RemoveVirtualNic(context.TODO(), device); err != nil {
+		return fmt.Errorf("error removing VMkernel adapter %s from host %s: %s", device, hostID, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceVSphereVNICImport imports a VMkernel adapter using the same
+// "<host_system_id>:<device>" format as the resource ID.
+func resourceVSphereVNICImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, _, err := splitHostNetworkID(d.Id()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// vnicSpec builds the parts of a VMkernel adapter specification that can be
+// changed after the adapter is created.
+func vnicSpec(d *schema.ResourceData) (*types.HostVirtualNicSpec, error) {
+	ip := &types.HostIpConfig{}
+	if v := d.Get("ipv4").([]interface{}); len(v) > 0 && v[0] != nil {
+		ipv4 := v[0].(map[string]interface{})
+		ip.Dhcp = ipv4["dhcp"].(bool)
+		if !ip.Dhcp {
+			ip.IpAddress = ipv4["ip"].(string)
+			ip.SubnetMask = ipv4["netmask"].(string)
+			if ip.IpAddress == "" || ip.SubnetMask == "" {
+				return nil, fmt.Errorf("Both ip and netmask must be set in ipv4 when dhcp is false.")
+			}
+		}
+	}
+	if v := d.Get("ipv6").([]interface{}); len(v) > 0 && v[0] != nil {
+		ipv6 := v[0].(map[string]interface{})
+		addresses, err := expandVNICIPv6Addresses(v, string(types.HostConfigChangeOperationAdd))
+		if err != nil {
+			return nil, err
+		}
+		ip.IpV6Config = &types.HostIpConfigIpV6AddressConfiguration{
+			DhcpV6Enabled:            types.NewBool(ipv6["dhcp"].(bool)),
+			AutoConfigurationEnabled: types.NewBool(ipv6["autoconfig"].(bool)),
+			IpV6Address:              addresses,
+		}
+	}
+
+	return &types.HostVirtualNicSpec{
+		Ip:  ip,
+		Mtu: int32(d.Get("mtu").(int)),
+	}, nil
+}
+
+// expandVNICIPv6Addresses turns the addresses of an ipv6 block, in CIDR
+// notation, into address specifications for the given operation.
+func expandVNICIPv6Addresses(v []interface{}, op string) ([]types.HostIpConfigIpV6Address, error) {
+	var addresses []types.HostIpConfigIpV6Address
+	if len(v) == 0 || v[0] == nil {
+		return addresses, nil
+	}
+	for _, a := range v[0].(map[string]interface{})["addresses"].([]interface{}) {
+		parts := strings.SplitN(a.(string), "/", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid IPv6 address %q, expected CIDR notation such as fd00::1/64", a)
+		}
+		prefix, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid IPv6 address %q: %s", a, err)
+		}
+		addresses = append(addresses, types.HostIpConfigIpV6Address{
+			IpAddress:    parts[0],
+			PrefixLength: int32(prefix),
+			Operation:    op,
+		})
+	}
+	return addresses, nil
+}
+
+// vnicIPv6Addresses returns the manually configured IPv6 addresses of an
+// adapter in CIDR notation, leaving out link-local and autoconfigured ones.
+func vnicIPv6Addresses(config *types.HostIpConfigIpV6AddressConfiguration) []string {
+	addresses := make([]string, 0)
+	for _, a := range config.IpV6Address {
+		if a.Origin != string(types.HostIpConfigIpV6AddressConfigTypeManual) {
+			continue
+		}
+		addresses = append(addresses, fmt.Sprintf("%s/%d", a.IpAddress, a.PrefixLength))
+	}
+	return addresses
+}
+
+func vnicFromDevice(client *govmomi.Client, hostID, device string) (*types.HostVirtualNic, error) {
+	ns, err := hostNetworkSystemFromHostSystemID(client, hostID)
+	if err != nil {
+		return nil, err
+	}
+	info, err := hostNetworkInfo(ns)
+	if err != nil {
+		return nil, err
+	}
+	for i := range info.Vnic {
+		if info.Vnic[i].Device == device {
+			return &info.Vnic[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func vnicManagerFromHostSystemID(client *govmomi.Client, hostID string) (*object.HostVirtualNicManager, error) {
+	host := object.NewHostSystem(client.Client, hostSystemReference(hostID))
+	vnm, err := host.ConfigManager().VirtualNicManager(context.TODO())
+	if err != nil {
+		return nil, fmt.Errorf("error finding virtual NIC manager of host %s: %s", hostID, err)
+	}
+	return vnm, nil
+}
+
+// vnicServicesForDevice returns the services, such as vmotion, that are
+// enabled on an adapter.
+func vnicServicesForDevice(client *govmomi.Client, hostID, device string) ([]string, error) {
+	vnm, err := vnicManagerFromHostSystemID(client, hostID)
+	if err != nil {
+		return nil, err
+	}
+	info, err := vnm.Info(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	services := make([]string, 0)
+	for _, config := range info.NetConfig {
+		for _, candidate := range config.CandidateVnic {
+			if candidate.Device != device {
+				continue
+			}
+			for _, selected := range config.SelectedVnic {
+				if selected == candidate.Key {
+					services = append(services, config.NicType)
+				}
+			}
+		}
+	}
+	return services, nil
+}
+
+// vnicSelectServices enables the services that are in n but not in o on an
+// adapter, and disables the ones that are in o but not in n.
+func vnicSelectServices(client *govmomi.Client, hostID, device string, o, n []interface{}) error {
+	vnm, err := vnicManagerFromHostSystemID(client, hostID)
+	if err != nil {
+		return err
+	}
+
+	oldServices := schema.NewSet(schema.HashString, o)
+	newServices := schema.NewSet(schema.HashString, n)
+	for _, service := range oldServices.Difference(newServices).List() {
+		if err := vnm.DeselectVnic(context.TODO(), service.(string), device); err != nil {
+			return fmt.Errorf("error disabling %s on VMkernel adapter %s: %s", service, device, err)
+		}
+	}
+	for _, service := range newServices.Difference(oldServices).List() {
+		if err := vnm.SelectVnic(context.TODO(), service.(string), device); err != nil {
+			return fmt.Errorf("error enabling %s on VMkernel adapter %s: %s", service, device, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/vmware/govmomi"
+)
+
+func TestAccVSphereVNIC_basic(t *testing.T) {
+	host := os.Getenv("VSPHERE_HOST_SYSTEM_ID")
+	nic := os.Getenv("VSPHERE_HOST_NIC")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccVSphereHostNetworkPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereVNICDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereVNICConfig, host, nic, nic),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereVNICExists("vsphere_vnic.vnic"),
+					resource.TestCheckResourceAttr("vsphere_vnic.vnic", "ipv4.0.ip", "192.168.199.10"),
+					resource.TestCheckResourceAttr("vsphere_vnic.vnic", "mtu", "1500"),
+					resource.TestCheckResourceAttr("vsphere_vnic.vnic", "services.#", "1"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckVSphereVNICConfigUpdated, host, nic, nic),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereVNICExists("vsphere_vnic.vnic"),
+					resource.TestCheckResourceAttr("vsphere_vnic.vnic", "ipv4.0.ip", "192.168.199.11"),
+					resource.TestCheckResourceAttr("vsphere_vnic.vnic", "mtu", "9000"),
+					resource.TestCheckResourceAttr("vsphere_vnic.vnic", "services.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "vsphere_vnic.vnic",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckVSphereVNICDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*govmomi.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "vsphere_vnic" {
+			continue
+		}
+
+		hostID, device, err := splitHostNetworkID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		vnic, err := vnicFromDevice(client, hostID, device)
+		if err != nil {
+			return err
+		}
+		if vnic != nil {
+			return fmt.Errorf("VMkernel adapter %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckVSphereVNICExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		client := testAccProvider.Meta().(*govmomi.Client)
+		hostID, device, err := splitHostNetworkID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		vnic, err := vnicFromDevice(client, hostID, device)
+		if err != nil {
+			return err
+		}
+		if vnic == nil {
+			return fmt.Errorf("VMkernel adapter %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+const testAccCheckVSphereVNICConfig = `
+resource "vsphere_host_virtual_switch" "switch" {
+  name             = "vSwitchTerraformTest"
+  host_system_id   = "%s"
+  network_adapters = ["%s"]
+  active_nics      = ["%s"]
+}
+
+resource "vsphere_host_port_group" "pg" {
+  name                = "PGTerraformTest"
+  host_system_id      = "${vsphere_host_virtual_switch.switch.host_system_id}"
+  virtual_switch_name = "${vsphere_host_virtual_switch.switch.name}"
+}
+
+resource "vsphere_vnic" "vnic" {
+  host_system_id = "${vsphere_host_port_group.pg.host_system_id}"
+  portgroup      = "${vsphere_host_port_group.pg.name}"
+
+  ipv4 {
+    ip      = "192.168.199.10"
+    netmask = "255.255.255.0"
+  }
+
+  services = ["vmotion"]
+}
+`
+
+const testAccCheckVSphereVNICConfigUpdated = `
+resource "vsphere_host_virtual_switch" "switch" {
+  name             = "vSwitchTerraformTest"
+  host_system_id   = "%s"
+  network_adapters = ["%s"]
+  active_nics      = ["%s"]
+  mtu              = 9000
+}
+
+resource "vsphere_host_port_group" "pg" {
+  name                = "PGTerraformTest"
+  host_system_id      = "${vsphere_host_virtual_switch.switch.host_system_id}"
+  virtual_switch_name = "${vsphere_host_virtual_switch.switch.name}"
+}
+
+resource "vsphere_vnic" "vnic" {
+  host_system_id = "${vsphere_host_port_group.pg.host_system_id}"
+  portgroup      = "${vsphere_host_port_group.pg.name}"
+  mtu            = 9000
+
+  ipv4 {
+    ip      = "192.168.199.11"
+    netmask = "255.255.255.0"
+  }
+
+  services = ["vmotion", "vSphereProvisioning"]
+}
+`
+
+func TestVNICSpec_ipv4WithoutAddress(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceVSphereVNIC().Schema, map[string]interface{}{
+		"ipv4": []interface{}{
+			map[string]interface{}{"ip": "192.168.0.10"},
+		},
+	})
+	if _, err := vnicSpec(d); err == nil {
+		t.Fatal("expected an error for a static ipv4 block without a netmask")
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceVSphereVNIC().Schema, map[string]interface{}{
+		"ipv4": []interface{}{
+			map[string]interface{}{"dhcp": true},
+		},
+	})
+	spec, err := vnicSpec(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !spec.Ip.Dhcp {
+		t.Fatal("expected DHCP to be enabled")
+	}
+}